@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"time"
@@ -28,19 +29,31 @@ func main() {
 
 	for {
 		select {
-		case event := <-events:
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
 			var data map[string]interface{}
 			err := event.As(&data)
-			if err != nil {
-				panic(err)
+			if errors.Is(err, sailhouse.ErrNoData) {
+				// Nothing to process, so acknowledge it and move on
+				fmt.Println(fmt.Sprintf("Event %s has no data", event.ID))
+			} else if err != nil {
+				fmt.Println(fmt.Sprintf("Failed to decode event %s: %v", event.ID, err))
+				continue
+			} else {
+				fmt.Println(fmt.Sprintf("Event: %v", data))
 			}
-			fmt.Println(fmt.Sprintf("Event: %v", data))
 			err = event.Ack(ctx)
 			if err != nil {
-				panic(err)
+				fmt.Println(fmt.Sprintf("Failed to ack event %s: %v", event.ID, err))
+			}
+		case err, ok := <-errs:
+			if !ok {
+				return
 			}
-		case err := <-errs:
-			panic(err)
+			// Errors like a malformed message don't end the stream; it ends when events is closed
+			fmt.Println(err)
 		case <-ctx.Done():
 			return
 		}
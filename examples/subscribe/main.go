@@ -15,7 +15,7 @@ func main() {
 	defer cancel()
 
 	client := sailhouse.NewSailhouseClient(token)
-	client.Subscribe(ctx, "example-topic", "example-subscription", func(ctx context.Context, e *sailhouse.Event) {
+	sub := client.Subscribe(ctx, "example-topic", "example-subscription", func(ctx context.Context, e *sailhouse.Event) {
 		message := fmt.Sprintf("Received event: %s", e.ID)
 
 		var data map[string]interface{}
@@ -40,8 +40,5 @@ func main() {
 		ExitOnErr: true,
 	})
 
-	select {
-	case <-ctx.Done():
-		return
-	}
+	<-sub.Done()
 }
@@ -1,7 +1,12 @@
 module github.com/sailhouse/sdk-go
 
-go 1.20
+go 1.21
 
-require github.com/gorilla/websocket v1.5.1
+require (
+	github.com/gorilla/websocket v1.5.1
+	go.opentelemetry.io/otel v1.24.0
+	go.opentelemetry.io/otel/metric v1.24.0
+	go.opentelemetry.io/otel/trace v1.24.0
+)
 
 require golang.org/x/net v0.17.0 // indirect
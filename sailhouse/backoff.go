@@ -0,0 +1,88 @@
+package sailhouse
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// BackoffStrategy computes the delay before retrying something, such as a request or a pull.
+type BackoffStrategy interface {
+	// NextDelay returns the delay to wait before the given attempt, starting from 0.
+	NextDelay(attempt int) time.Duration
+	// Reset is called once whatever was being retried succeeds, for strategies that keep state.
+	Reset()
+}
+
+// ExponentialBackoff computes delays that grow by Factor on every attempt,
+// capped at Max.
+//
+// Jitter is the fraction (between 0 and 1) of each delay that's randomised, so
+// a Jitter of 0.2 returns a delay between 80% and 100% of the computed value.
+type ExponentialBackoff struct {
+	Base   time.Duration
+	Max    time.Duration
+	Factor float64
+	Jitter float64
+}
+
+// Next returns the delay to wait before the given attempt, starting from 0.
+func (b ExponentialBackoff) Next(attempt int) time.Duration {
+	if attempt < 0 {
+		attempt = 0
+	}
+
+	factor := b.Factor
+	if factor <= 0 {
+		factor = 2
+	}
+
+	delay := float64(b.Base) * math.Pow(factor, float64(attempt))
+	if b.Max > 0 && delay > float64(b.Max) {
+		delay = float64(b.Max)
+	}
+
+	if b.Jitter > 0 {
+		jitter := math.Min(b.Jitter, 1)
+		delay -= delay * jitter * rand.Float64()
+	}
+
+	return time.Duration(delay)
+}
+
+func (b ExponentialBackoff) NextDelay(attempt int) time.Duration {
+	return b.Next(attempt)
+}
+
+func (ExponentialBackoff) Reset() {}
+
+// ConstantBackoff waits the same Delay before every attempt.
+type ConstantBackoff struct {
+	Delay time.Duration
+}
+
+func (b ConstantBackoff) NextDelay(int) time.Duration {
+	return b.Delay
+}
+
+func (ConstantBackoff) Reset() {}
+
+// JitteredBackoff randomly shortens the delays of another strategy by up to the Jitter
+// fraction (between 0 and 1), so clients retrying together spread out.
+type JitteredBackoff struct {
+	Strategy BackoffStrategy
+	Jitter   float64
+}
+
+func (b JitteredBackoff) NextDelay(attempt int) time.Duration {
+	delay := float64(b.Strategy.NextDelay(attempt))
+	if b.Jitter > 0 {
+		delay -= delay * math.Min(b.Jitter, 1) * rand.Float64()
+	}
+
+	return time.Duration(delay)
+}
+
+func (b JitteredBackoff) Reset() {
+	b.Strategy.Reset()
+}
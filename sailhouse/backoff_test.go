@@ -0,0 +1,83 @@
+package sailhouse
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExponentialBackoffGrowsAndCaps(t *testing.T) {
+	b := ExponentialBackoff{Base: 100 * time.Millisecond, Max: time.Second, Factor: 2}
+
+	want := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}
+	for attempt, w := range want {
+		if got := b.Next(attempt); got != w {
+			t.Errorf("attempt %d: expected %s, got %s", attempt, w, got)
+		}
+	}
+}
+
+func TestExponentialBackoffDefaults(t *testing.T) {
+	b := ExponentialBackoff{Base: time.Second}
+
+	if got := b.Next(2); got != 4*time.Second {
+		t.Errorf("expected a default factor of 2, got %s", got)
+	}
+	if got := b.Next(-1); got != time.Second {
+		t.Errorf("expected negative attempts to use the base delay, got %s", got)
+	}
+}
+
+func TestExponentialBackoffJitterBounds(t *testing.T) {
+	b := ExponentialBackoff{Base: time.Second, Max: time.Second, Jitter: 0.2}
+
+	for i := 0; i < 1000; i++ {
+		got := b.Next(0)
+		if got < 800*time.Millisecond || got > time.Second {
+			t.Fatalf("expected a delay between 800ms and 1s, got %s", got)
+		}
+	}
+}
+
+func TestExponentialBackoffJitterClamped(t *testing.T) {
+	b := ExponentialBackoff{Base: time.Second, Jitter: 5}
+
+	for i := 0; i < 1000; i++ {
+		if got := b.Next(0); got < 0 || got > time.Second {
+			t.Fatalf("expected jitter above 1 to be clamped, got %s", got)
+		}
+	}
+}
+
+func TestJitteredBackoffBounds(t *testing.T) {
+	b := JitteredBackoff{Strategy: ConstantBackoff{Delay: time.Second}, Jitter: 0.25}
+
+	for i := 0; i < 1000; i++ {
+		got := b.NextDelay(i)
+		if got < 750*time.Millisecond || got > time.Second {
+			t.Fatalf("expected a delay between 750ms and 1s, got %s", got)
+		}
+	}
+}
+
+func TestJitteredBackoffClampedAndReset(t *testing.T) {
+	inner := &recordingBackoff{}
+	b := JitteredBackoff{Strategy: inner, Jitter: 5}
+
+	for i := 0; i < 1000; i++ {
+		if got := b.NextDelay(0); got < 0 || got > time.Millisecond {
+			t.Fatalf("expected jitter above 1 to be clamped, got %s", got)
+		}
+	}
+
+	b.Reset()
+	if inner.resets != 1 {
+		t.Errorf("expected Reset to reach the wrapped strategy, got %d resets", inner.resets)
+	}
+}
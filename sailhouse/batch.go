@@ -0,0 +1,176 @@
+package sailhouse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// BatchEvent is a single event published as part of PublishBatch.
+type BatchEvent struct {
+	Body any
+	// Metadata overrides any metadata set with WithMetaData for this event.
+	Metadata map[string]any
+}
+
+type PublishResponse struct {
+	ID string `json:"id"`
+}
+
+// BatchFailure describes an event in a batch that failed to publish.
+type BatchFailure struct {
+	Index   int
+	Message string
+}
+
+// BatchPublishError is returned by PublishBatch when some of the events in the batch failed.
+type BatchPublishError struct {
+	Total    int
+	Failures []BatchFailure
+}
+
+func (e *BatchPublishError) Error() string {
+	return fmt.Sprintf("failed to publish %d of %d events", len(e.Failures), e.Total)
+}
+
+// ErrEventIDInBatch is returned by PublishBatch when given WithEventID, which would give every
+// event in the batch the same ID.
+var ErrEventIDInBatch = errors.New("WithEventID can't be used with PublishBatch")
+
+type batchResult struct {
+	ID    string `json:"id"`
+	Error string `json:"error,omitempty"`
+}
+
+type batchResponse struct {
+	Results []batchResult `json:"results"`
+}
+
+// PublishBatch publishes many events to a topic in a single request.
+//
+// Options apply to every event in the batch. The returned responses are aligned by index
+// with events; if only some events failed, the error is a *BatchPublishError listing them.
+func (c *SailhouseClient) PublishBatch(ctx context.Context, topic string, events []BatchEvent, opts ...publishOpt) ([]PublishResponse, error) {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
+
+	if c.stampCaller {
+		opts = append(opts[:len(opts):len(opts)], withCaller(captureCaller(1)))
+	}
+
+	ctx, span := c.startSpan(ctx, "sailhouse.publish", "publish", trace.SpanKindProducer, topic, "")
+	span.SetAttributes(attribute.Int("messaging.batch.message_count", len(events)))
+	start := time.Now()
+	res, err := c.publishBatch(ctx, topic, events, opts...)
+	endSpan(span, err)
+	c.instruments.recordDuration(ctx, c.instruments.publishDuration, time.Since(start), "publish", topic, "")
+
+	var batchErr *BatchPublishError
+	if errors.As(err, &batchErr) {
+		attrs := metricAttrs("publish", topic, "")
+		c.instruments.published.Add(ctx, int64(len(events)-len(batchErr.Failures)), attrs)
+		c.instruments.failed.Add(ctx, int64(len(batchErr.Failures)), attrs)
+	} else {
+		c.instruments.recordResult(ctx, c.instruments.published, len(events), err, "publish", topic, "")
+	}
+
+	return res, err
+}
+
+func (c *SailhouseClient) publishBatch(ctx context.Context, topic string, events []BatchEvent, opts ...publishOpt) ([]PublishResponse, error) {
+	endpoint := fmt.Sprintf("%s/topics/%s/events/batch", c.baseURL, topic)
+
+	defaults := map[string]any{}
+	cfg, err := applyPublishOpts(defaults, opts)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.eventID != "" {
+		return nil, ErrEventIDInBatch
+	}
+
+	if cfg.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.timeout)
+		defer cancel()
+	}
+
+	body := make([]map[string]any, len(events))
+	for i, event := range events {
+		e := make(map[string]any, len(defaults)+1)
+		for k, v := range defaults {
+			e[k] = v
+		}
+		e[FieldData] = event.Body
+		if event.Metadata != nil {
+			e[FieldMetadata] = event.Metadata
+		}
+		if c.stampCaller {
+			stampCaller(e, cfg.caller)
+		}
+		c.injectTraceContext(ctx, e)
+
+		body[i] = c.renameFields(e)
+	}
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if cfg.idempotencyKey != "" {
+		req.Header.Set(idempotencyKeyHeader, cfg.idempotencyKey)
+	}
+
+	res, err := c.doPublish("publish_batch", req)
+	if err != nil {
+		return nil, opError("publish_batch", topic, "", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 201 && res.StatusCode != 207 {
+		return nil, newAPIError("publish_batch", topic, "", res)
+	}
+
+	var dest batchResponse
+	err = json.NewDecoder(res.Body).Decode(&dest)
+	if err != nil {
+		return nil, opError("publish_batch", topic, "", err)
+	}
+
+	responses := make([]PublishResponse, len(events))
+	batchErr := &BatchPublishError{Total: len(events)}
+	for i := range events {
+		if i >= len(dest.Results) {
+			batchErr.Failures = append(batchErr.Failures, BatchFailure{Index: i, Message: "missing from response"})
+			continue
+		}
+
+		result := dest.Results[i]
+		if result.Error != "" {
+			batchErr.Failures = append(batchErr.Failures, BatchFailure{Index: i, Message: result.Error})
+			continue
+		}
+
+		responses[i] = PublishResponse{ID: result.ID}
+	}
+
+	if len(batchErr.Failures) > 0 {
+		return responses, batchErr
+	}
+
+	return responses, nil
+}
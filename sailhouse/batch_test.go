@@ -0,0 +1,40 @@
+package sailhouse
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestPublishBatchPartialFailure(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		// The fourth event is missing from the results altogether
+		w.WriteHeader(http.StatusMultiStatus)
+		w.Write([]byte(`{"results":[{"id":"id-0"},{"error":"payload too large"},{"id":"id-2"}]}`))
+	})
+
+	events := []BatchEvent{{Body: "a"}, {Body: "b"}, {Body: "c"}, {Body: "d"}}
+	res, err := client.PublishBatch(context.Background(), "topic", events)
+
+	var batchErr *BatchPublishError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expected a BatchPublishError, got %v", err)
+	}
+	if batchErr.Total != 4 || len(batchErr.Failures) != 2 {
+		t.Fatalf("expected 2 of 4 events to fail, got %+v", batchErr)
+	}
+	if f := batchErr.Failures[0]; f.Index != 1 || f.Message != "payload too large" {
+		t.Errorf("expected index 1 to fail with the server's message, got %+v", f)
+	}
+	if f := batchErr.Failures[1]; f.Index != 3 || f.Message != "missing from response" {
+		t.Errorf("expected index 3 to be missing, got %+v", f)
+	}
+	if err.Error() != "failed to publish 2 of 4 events" {
+		t.Errorf("unexpected message %q", err)
+	}
+
+	if len(res) != 4 || res[0].ID != "id-0" || res[1].ID != "" || res[2].ID != "id-2" || res[3].ID != "" {
+		t.Errorf("expected responses aligned with the accepted events, got %+v", res)
+	}
+}
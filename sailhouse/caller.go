@@ -0,0 +1,60 @@
+package sailhouse
+
+import "runtime"
+
+// CallerMetadataKey is the metadata key StampCaller records the publishing call site under.
+const CallerMetadataKey = "_sailhouse_caller"
+
+type callerInfo struct {
+	function string
+	file     string
+	line     int
+}
+
+// captureCaller returns the call site skip frames above its caller.
+func captureCaller(skip int) *callerInfo {
+	pc, file, line, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return nil
+	}
+
+	caller := &callerInfo{file: file, line: line}
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		caller.function = fn.Name()
+	}
+
+	return caller
+}
+
+// withCaller records the call site to stamp into the event's metadata. The first call site
+// given wins, so wrappers like PublishStream can record their own caller.
+func withCaller(caller *callerInfo) publishOpt {
+	return publishOpt{
+		configure: func(cfg *publishConfig) {
+			if cfg.caller == nil {
+				cfg.caller = caller
+			}
+		},
+	}
+}
+
+// stampCaller adds the call site to the metadata of a request body.
+func stampCaller(body map[string]any, caller *callerInfo) {
+	if caller == nil {
+		return
+	}
+
+	metadata := map[string]any{}
+	if existing, ok := body[FieldMetadata].(map[string]any); ok {
+		for k, v := range existing {
+			metadata[k] = v
+		}
+	}
+	metadata[CallerMetadataKey] = map[string]any{
+		"function": caller.function,
+		"file":     caller.file,
+		"line":     caller.line,
+	}
+
+	body[FieldMetadata] = metadata
+}
@@ -0,0 +1,68 @@
+package sailhouse
+
+import "context"
+
+// maxCatchUpIDs bounds how many backlog event IDs ConsumeCatchUp remembers to skip once streaming.
+const maxCatchUpIDs = 1024
+
+// ConsumeCatchUp processes the subscription's backlog by pulling until no new events are
+// returned, then switches to streaming live events. Events handled from the backlog aren't
+// handled again if the stream delivers them too.
+//
+// It blocks until the context is cancelled or the stream ends, returning the error that ended it.
+// As there's no Subscription handle, in-flight events aren't tracked; use Subscribe for that.
+func (c *SailhouseClient) ConsumeCatchUp(ctx context.Context, topic string, subscription string, handler SubscriptionHandler) error {
+	ctx = orBackground(ctx)
+	seen := NewMemoryIdempotencyStore(maxCatchUpIDs)
+
+	for {
+		page, err := c.GetEvents(ctx, topic, subscription)
+		if err != nil {
+			return err
+		}
+
+		// Unacknowledged events may be pulled again, so only new events mean we're still behind
+		fresh := 0
+		for _, event := range page.Events {
+			if ok, _ := seen.Seen(ctx, event.ID); ok {
+				continue
+			}
+			fresh++
+			seen.Mark(ctx, event.ID)
+			handler(ctx, event)
+		}
+
+		if fresh == 0 {
+			break
+		}
+	}
+
+	events, errs := c.StreamEvents(ctx, topic, subscription)
+
+	var streamErr error
+	for events != nil || errs != nil {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			if ok, _ := seen.Seen(ctx, event.ID); ok {
+				continue
+			}
+			handler(ctx, &event)
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			streamErr = err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return streamErr
+}
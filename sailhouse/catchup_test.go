@@ -0,0 +1,59 @@
+package sailhouse
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestConsumeCatchUpOrdersBacklogBeforeStream(t *testing.T) {
+	var pulls int32
+	upgrader := websocket.Upgrader{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/events/stream" {
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			conn.ReadMessage()
+			// b2 was handled from the backlog, so only s1 is new
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"id":"b2","data":{}}`))
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"id":"s1","data":{}}`))
+			conn.ReadMessage()
+			return
+		}
+
+		// The second pull only returns an event that was already handled
+		if atomic.AddInt32(&pulls, 1) == 1 {
+			w.Write([]byte(`{"events":[{"id":"b1","data":{}},{"id":"b2","data":{}}]}`))
+			return
+		}
+		w.Write([]byte(`{"events":[{"id":"b2","data":{}}]}`))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var handled []string
+	err := client.ConsumeCatchUp(ctx, "topic", "sub", func(_ context.Context, e *Event) {
+		handled = append(handled, e.ID)
+		if e.ID == "s1" {
+			cancel()
+		}
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the context's error, got %v", err)
+	}
+	if want := []string{"b1", "b2", "s1"}; !reflect.DeepEqual(handled, want) {
+		t.Errorf("expected %v, got %v", want, handled)
+	}
+}
@@ -0,0 +1,132 @@
+package sailhouse
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by Publish and PublishBatch without making a request while the
+// publish circuit breaker is open.
+var ErrCircuitOpen = errors.New("publish circuit open")
+
+// PublishCircuitBreaker stops publishing for a while after repeated failures, so a failing
+// endpoint isn't hammered. Once OpenDuration has passed, a single publish is let through to
+// probe the endpoint, closing the circuit if it succeeds.
+//
+// Network errors, deadlines, 429s and 5xx responses count as failures. Errors from before the
+// request is sent, and waiting for WithDeliveryConfirmation, don't.
+type PublishCircuitBreaker struct {
+	// FailureThreshold is the number of consecutive failures that opens the circuit. Defaults to 5.
+	FailureThreshold int
+	// OpenDuration is how long the circuit stays open before probing. Defaults to 30 seconds.
+	OpenDuration time.Duration
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+type circuitBreaker struct {
+	threshold int
+	open      time.Duration
+
+	mu       sync.Mutex
+	state    circuitState
+	failures int
+	openedAt time.Time
+}
+
+func newCircuitBreaker(cfg *PublishCircuitBreaker) *circuitBreaker {
+	if cfg == nil {
+		return nil
+	}
+
+	cb := &circuitBreaker{
+		threshold: cfg.FailureThreshold,
+		open:      cfg.OpenDuration,
+	}
+	if cb.threshold <= 0 {
+		cb.threshold = 5
+	}
+	if cb.open <= 0 {
+		cb.open = 30 * time.Second
+	}
+
+	return cb
+}
+
+// allow reports whether a publish may go ahead. A nil breaker always allows it.
+func (cb *circuitBreaker) allow() error {
+	if cb == nil {
+		return nil
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case circuitOpen:
+		if time.Since(cb.openedAt) < cb.open {
+			return ErrCircuitOpen
+		}
+		cb.state = circuitHalfOpen
+		return nil
+	case circuitHalfOpen:
+		// Only the probe is let through
+		return ErrCircuitOpen
+	}
+
+	return nil
+}
+
+// record updates the breaker with the outcome of a request that allow let through. Network
+// errors, deadlines, 429s and 5xx responses are failures.
+func (cb *circuitBreaker) record(res *http.Response, err error) {
+	if cb == nil {
+		return
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if errors.Is(err, context.Canceled) {
+		// The caller gave up, which says nothing about the endpoint, so let another publish probe
+		if cb.state == circuitHalfOpen {
+			cb.state = circuitOpen
+		}
+		return
+	}
+
+	failed := err != nil || res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500
+	if !failed {
+		cb.state = circuitClosed
+		cb.failures = 0
+		return
+	}
+
+	cb.failures++
+	if cb.state == circuitHalfOpen || cb.failures >= cb.threshold {
+		cb.state = circuitOpen
+		cb.openedAt = time.Now()
+	}
+}
+
+// doPublish sends a publish request through the circuit breaker. Errors from before the request
+// is sent, such as invalid options, never reach the breaker.
+func (c *SailhouseClient) doPublish(op string, req *http.Request) (*http.Response, error) {
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(op, req)
+	c.breaker.record(res, err)
+
+	return res, err
+}
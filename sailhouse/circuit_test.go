@@ -0,0 +1,122 @@
+package sailhouse
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func breakerClient(t *testing.T, status *int32, hits *int32) *SailhouseClient {
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/status") {
+			w.Write([]byte(`{"delivered":false}`))
+			return
+		}
+		atomic.AddInt32(hits, 1)
+		w.WriteHeader(int(atomic.LoadInt32(status)))
+		w.Write([]byte(`{"id":"event-id"}`))
+	}, func(o *SailhouseClientOptions) {
+		o.PublishCircuitBreaker = &PublishCircuitBreaker{FailureThreshold: 2, OpenDuration: 50 * time.Millisecond}
+	})
+}
+
+func TestCircuitBreakerTripsAndRecovers(t *testing.T) {
+	status := int32(http.StatusServiceUnavailable)
+	var hits int32
+	client := breakerClient(t, &status, &hits)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := client.Publish(ctx, "topic", "data"); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("publish %d: expected the endpoint's error, got %v", i, err)
+		}
+	}
+
+	if err := client.Publish(ctx, "topic", "data"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen once tripped, got %v", err)
+	}
+	if hits != 2 {
+		t.Errorf("expected the open circuit to skip the request, got %d requests", hits)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	atomic.StoreInt32(&status, http.StatusCreated)
+
+	if err := client.Publish(ctx, "topic", "data"); err != nil {
+		t.Fatalf("expected the half-open probe to succeed, got %v", err)
+	}
+	if err := client.Publish(ctx, "topic", "data"); err != nil {
+		t.Fatalf("expected the circuit to be closed, got %v", err)
+	}
+}
+
+func TestCircuitBreakerFailedProbeReopens(t *testing.T) {
+	status := int32(http.StatusServiceUnavailable)
+	var hits int32
+	client := breakerClient(t, &status, &hits)
+	ctx := context.Background()
+
+	client.Publish(ctx, "topic", "data")
+	client.Publish(ctx, "topic", "data")
+	time.Sleep(60 * time.Millisecond)
+
+	if err := client.Publish(ctx, "topic", "data"); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected the probe to reach the endpoint and fail, got %v", err)
+	}
+	if err := client.Publish(ctx, "topic", "data"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected the failed probe to reopen the circuit, got %v", err)
+	}
+}
+
+func TestCircuitBreakerIgnoresNonEndpointErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int32
+		data    any
+		options []publishOpt
+	}{
+		{name: "unencodable data", status: http.StatusCreated, data: func() {}},
+		{name: "invalid metadata", status: http.StatusCreated, data: "data", options: []publishOpt{WithMetaDataStruct(1)}},
+		{name: "unconfirmed delivery", status: http.StatusCreated, data: "data", options: []publishOpt{WithDeliveryConfirmation(time.Millisecond)}},
+		{name: "client error", status: http.StatusBadRequest, data: "data"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := tt.status
+			var hits int32
+			client := breakerClient(t, &status, &hits)
+			ctx := context.Background()
+
+			for i := 0; i < 3; i++ {
+				if err := client.Publish(ctx, "topic", tt.data, tt.options...); err == nil {
+					t.Fatalf("publish %d: expected an error", i)
+				}
+			}
+
+			atomic.StoreInt32(&status, http.StatusCreated)
+			if err := client.Publish(ctx, "topic", "data"); err != nil {
+				t.Fatalf("expected the circuit to stay closed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerAppliesToBatches(t *testing.T) {
+	status := int32(http.StatusInternalServerError)
+	var hits int32
+	client := breakerClient(t, &status, &hits)
+	ctx := context.Background()
+	events := []BatchEvent{{Body: "a"}}
+
+	client.PublishBatch(ctx, "topic", events)
+	client.PublishBatch(ctx, "topic", events)
+
+	if _, err := client.PublishBatch(ctx, "topic", events); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+}
@@ -4,19 +4,38 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type SailhouseClient struct {
-	client *http.Client
-	token  string
+	baseURL       string
+	client        *http.Client
+	untimedClient *http.Client
+	token         string
+	consumerInfo  string
+	dialer        *websocket.Dialer
+	responseHook  ResponseHook
+	fieldNames    map[string]string
+	emptyPoll     []int
+	retryPolicy   *RetryPolicy
+	logger        *slog.Logger
+	tracer        trace.Tracer
+	tracing       bool
+	instruments   *instruments
+	stampCaller   bool
+	breaker       *circuitBreaker
 }
 
 const BaseURL = "https://api.sailhouse.dev"
@@ -24,8 +43,50 @@ const BaseURL = "https://api.sailhouse.dev"
 type SailhouseClientOptions struct {
 	Client *http.Client
 	Token  string
+	// BaseURL overrides the API's base URL, e.g. for a staging environment or a test server.
+	// It may include a path prefix, such as "https://gateway.example.com/sailhouse".
+	// Defaults to BaseURL.
+	BaseURL string
+	// ConsumerInfo identifies this consumer (e.g. version, hostname) to the server.
+	// It's sent as the x-consumer-info header on pull requests.
+	ConsumerInfo map[string]string
+	// SkipTLSForHosts disables TLS certificate verification for the listed hosts only,
+	// e.g. a local proxy, while every other host is still verified. Hosts are bare
+	// hostnames or IPs without a port, e.g. "localhost".
+	//
+	// This applies to the default HTTP client and to streaming. A custom Client is used as-is.
+	SkipTLSForHosts []string
+	// ResponseHook is called after every HTTP call, e.g. for metrics or logging.
+	ResponseHook ResponseHook
+	// FieldNames overrides the field names used in publish request bodies,
+	// keyed by the default name, e.g. {FieldSendAt: "deliver_at"}.
+	FieldNames map[string]string
+	// EmptyPollStatusCodes are the status codes treated as "no events available" when
+	// pulling events, rather than as errors. Defaults to 204. A 200 with an empty body is
+	// always treated as an empty poll.
+	EmptyPollStatusCodes []int
+	// RetryPolicy retries failed requests with exponential backoff. Requests aren't retried when nil.
+	RetryPolicy *RetryPolicy
+	// Logger receives request logs at debug level and retries at warn level. Nothing is logged when nil.
+	Logger *slog.Logger
+	// TracerProvider enables OpenTelemetry tracing of publishes, pulls, acks and Subscribe
+	// handlers. Trace context is propagated to consumers through event metadata.
+	TracerProvider trace.TracerProvider
+	// MeterProvider enables OpenTelemetry metrics: counters of published, consumed, acked, nacked and
+	// failed events, and histograms of publish and Subscribe handler latency.
+	MeterProvider metric.MeterProvider
+	// PublishCircuitBreaker makes publishes fail fast with ErrCircuitOpen after repeated failures.
+	// Disabled when nil.
+	PublishCircuitBreaker *PublishCircuitBreaker
+	// StampCaller records the function, file and line that published each event in its
+	// metadata under CallerMetadataKey, to help trace where events come from.
+	StampCaller bool
 }
 
+// ResponseHook is called after every HTTP call the client makes, including failed ones
+// where resp is nil. op names the operation, e.g. "publish" or "get_events".
+type ResponseHook func(op string, req *http.Request, resp *http.Response, err error, dur time.Duration)
+
 type Map map[string]interface{}
 
 func NewSailhouseClient(token string) *SailhouseClient {
@@ -35,23 +96,138 @@ func NewSailhouseClient(token string) *SailhouseClient {
 }
 
 func NewSailhouseClientWithOptions(opts SailhouseClientOptions) *SailhouseClient {
+	dialer := websocket.DefaultDialer
+
 	if opts.Client == nil {
 		opts.Client = &http.Client{
 			Timeout: 5 * time.Second,
 		}
+
+		if len(opts.SkipTLSForHosts) > 0 {
+			transport := http.DefaultTransport.(*http.Transport).Clone()
+			transport.DialTLSContext = skipTLSDialer(opts.SkipTLSForHosts, transport.TLSClientConfig, []string{"h2", "http/1.1"})
+			opts.Client.Transport = transport
+		}
+	}
+
+	if len(opts.SkipTLSForHosts) > 0 {
+		d := *websocket.DefaultDialer
+		// Websockets are only upgraded over HTTP/1.1
+		d.NetDialTLSContext = skipTLSDialer(opts.SkipTLSForHosts, d.TLSClientConfig, []string{"http/1.1"})
+		dialer = &d
+	}
+
+	if opts.BaseURL == "" {
+		opts.BaseURL = BaseURL
+	}
+	// Endpoints are appended to the base URL, so a trailing slash would double up
+	opts.BaseURL = strings.TrimRight(opts.BaseURL, "/")
+
+	if opts.Logger == nil {
+		opts.Logger = discardLogger
+	}
+
+	if len(opts.EmptyPollStatusCodes) == 0 {
+		opts.EmptyPollStatusCodes = []int{http.StatusNoContent}
+	}
+
+	untimedClient := *opts.Client
+	untimedClient.Timeout = 0
+
+	consumerInfo := url.Values{}
+	for k, v := range opts.ConsumerInfo {
+		consumerInfo.Set(k, v)
 	}
 
 	return &SailhouseClient{
-		client: opts.Client,
-		token:  opts.Token,
+		baseURL:       opts.BaseURL,
+		client:        opts.Client,
+		untimedClient: &untimedClient,
+		token:         opts.Token,
+		consumerInfo:  consumerInfo.Encode(),
+		dialer:        dialer,
+		responseHook:  opts.ResponseHook,
+		fieldNames:    opts.FieldNames,
+		emptyPoll:     opts.EmptyPollStatusCodes,
+		retryPolicy:   opts.RetryPolicy,
+		logger:        opts.Logger,
+		tracer:        newTracer(opts.TracerProvider),
+		tracing:       opts.TracerProvider != nil,
+		instruments:   newInstruments(opts.MeterProvider),
+		stampCaller:   opts.StampCaller,
+		breaker:       newCircuitBreaker(opts.PublishCircuitBreaker),
 	}
 }
 
-func (c *SailhouseClient) do(req *http.Request) (*http.Response, error) {
+func (c *SailhouseClient) do(op string, req *http.Request) (*http.Response, error) {
+	return c.doWith(c.client, op, req)
+}
+
+// doWith is do using the given HTTP client, e.g. one without the client's timeout.
+func (c *SailhouseClient) doWith(client *http.Client, op string, req *http.Request) (*http.Response, error) {
 	req.Header.Set("Authorization", c.token)
 	req.Header.Set("x-source", "sailhouse-go")
 
-	return c.client.Do(req)
+	attempts := c.retryPolicy.attemptsFor(req)
+
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		start := time.Now()
+		res, err := client.Do(req)
+		dur := time.Since(start)
+
+		if c.responseHook != nil {
+			c.responseHook(op, req, res, err, dur)
+		}
+
+		if err != nil {
+			c.logger.Debug("sailhouse request failed", "op", op, "method", req.Method, "path", req.URL.Path, "duration", dur, "error", err)
+		} else {
+			c.logger.Debug("sailhouse request", "op", op, "method", req.Method, "path", req.URL.Path, "status", res.StatusCode, "duration", dur)
+		}
+
+		if err == nil && (attempt >= attempts || !c.retryPolicy.retryableStatus(res.StatusCode)) {
+			if err := decompress(res); err != nil {
+				return nil, err
+			}
+			if attempts > 1 && c.retryPolicy.retryableStatus(res.StatusCode) {
+				res.Body = &retriedBody{ReadCloser: res.Body, attempts: attempt}
+			}
+			return res, nil
+		}
+
+		if err != nil && attempt >= attempts {
+			if attempts > 1 {
+				return nil, &RetryError{Attempts: attempt, Err: err}
+			}
+			return nil, err
+		}
+
+		delay := c.retryPolicy.backoff().NextDelay(attempt - 1)
+		if res != nil {
+			if res.StatusCode == http.StatusTooManyRequests {
+				if after, ok := retryAfter(res); ok {
+					delay = after
+				}
+			}
+			discard(res)
+		}
+
+		c.logger.Warn("retrying sailhouse request", "op", op, "method", req.Method, "path", req.URL.Path, "attempt", attempt, "delay", delay)
+
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return nil, &RetryError{Attempts: attempt, Err: req.Context().Err()}
+		}
+	}
 }
 
 type Events struct {
@@ -59,14 +235,33 @@ type Events struct {
 }
 
 type getOption struct {
-	mod (func(*http.Request))
+	mod             (func(*http.Request))
+	timeout         time.Duration
+	responseTimeout time.Duration
+	maxEvents       int
+}
+
+// WithRequestTimeout bounds how long the call may take, independent of the client's timeout.
+// The client's timeout still applies as an upper limit.
+func WithRequestTimeout(timeout time.Duration) getOption {
+	return getOption{
+		timeout: timeout,
+	}
+}
+
+// WithResponseTimeout bounds how long the call may take in place of the client's timeout,
+// so a long-poll pull can wait longer than unary calls are allowed to.
+func WithResponseTimeout(timeout time.Duration) getOption {
+	return getOption{
+		responseTimeout: timeout,
+	}
 }
 
 func WithLimit(limit int) getOption {
 	return getOption{
 		mod: func(req *http.Request) {
 			q := req.URL.Query()
-			q.Add("limit", fmt.Sprintf("%d", limit))
+			q.Set("limit", fmt.Sprintf("%d", limit))
 			req.URL.RawQuery = q.Encode()
 		},
 	}
@@ -76,7 +271,7 @@ func WithOffset(offset int) getOption {
 	return getOption{
 		mod: func(req *http.Request) {
 			q := req.URL.Query()
-			q.Add("offset", fmt.Sprintf("%d", offset))
+			q.Set("offset", fmt.Sprintf("%d", offset))
 			req.URL.RawQuery = q.Encode()
 		},
 	}
@@ -92,8 +287,54 @@ func WithTimeWindow(dur time.Duration) getOption {
 	}
 }
 
+// WithWait asks the server to hold the request for up to the given duration until events are
+// available, rather than returning an empty response straight away.
+func WithWait(dur time.Duration) getOption {
+	return getOption{
+		mod: func(req *http.Request) {
+			q := req.URL.Query()
+			q.Set("wait", dur.String())
+			req.URL.RawQuery = q.Encode()
+		},
+	}
+}
+
+// WithMaxEvents caps the number of events GetEventsAll collects.
+func WithMaxEvents(max int) getOption {
+	return getOption{
+		maxEvents: max,
+	}
+}
+
 func (c *SailhouseClient) GetEvents(ctx context.Context, topic, subscription string, opts ...getOption) (GetEventsResponse, error) {
-	endpoint := fmt.Sprintf("%s/topics/%s/subscriptions/%s/events", BaseURL, topic, subscription)
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
+
+	ctx, span := c.startSpan(ctx, "sailhouse.receive", "receive", trace.SpanKindConsumer, topic, subscription)
+	res, err := c.getEvents(ctx, topic, subscription, opts...)
+	endSpan(span, err)
+	c.instruments.recordResult(ctx, c.instruments.consumed, len(res.Events), err, "receive", topic, subscription)
+
+	return res, err
+}
+
+func (c *SailhouseClient) getEvents(ctx context.Context, topic, subscription string, opts ...getOption) (GetEventsResponse, error) {
+	endpoint := fmt.Sprintf("%s/topics/%s/subscriptions/%s/events", c.baseURL, topic, subscription)
+
+	httpClient := c.client
+	for _, opt := range opts {
+		if opt.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, opt.timeout)
+			defer cancel()
+		}
+		if opt.responseTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, opt.responseTimeout)
+			defer cancel()
+			httpClient = c.untimedClient
+		}
+	}
 
 	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
@@ -101,22 +342,40 @@ func (c *SailhouseClient) GetEvents(ctx context.Context, topic, subscription str
 	}
 
 	for _, opt := range opts {
-		opt.mod(req)
+		if opt.mod != nil {
+			opt.mod(req)
+		}
+	}
+
+	if c.consumerInfo != "" {
+		req.Header.Set("x-consumer-info", c.consumerInfo)
 	}
 
-	res, err := c.do(req)
+	res, err := c.doWith(httpClient, "get_events", req)
 	if err != nil {
-		return GetEventsResponse{}, err
+		return GetEventsResponse{}, opError("get_events", topic, subscription, err)
+	}
+
+	for _, code := range c.emptyPoll {
+		if res.StatusCode == code {
+			res.Body.Close()
+			return GetEventsResponse{}, nil
+		}
 	}
 
 	if res.StatusCode != 200 {
-		return GetEventsResponse{}, fmt.Errorf("failed to get events: %d", res.StatusCode)
+		return GetEventsResponse{}, newAPIError("get_events", topic, subscription, res)
 	}
+	defer res.Body.Close()
 
 	var dest GetEventsResponse
 	err = json.NewDecoder(res.Body).Decode(&dest)
+	if errors.Is(err, io.EOF) {
+		// Some gateways answer an empty poll with a 200 and no body
+		return GetEventsResponse{}, nil
+	}
 	if err != nil {
-		return GetEventsResponse{}, err
+		return GetEventsResponse{}, opError("get_events", topic, subscription, err)
 	}
 
 	for _, d := range dest.Events {
@@ -128,15 +387,131 @@ func (c *SailhouseClient) GetEvents(ctx context.Context, topic, subscription str
 	return dest, nil
 }
 
+// GetEventsAll pages through GetEvents until an empty page is returned, collecting every event.
+//
+// Use WithMaxEvents to bound how many events are collected. If a page fails or the context is
+// cancelled, the events collected so far are returned along with the error.
+func (c *SailhouseClient) GetEventsAll(ctx context.Context, topic, subscription string, opts ...getOption) ([]*Event, error) {
+	ctx = orBackground(ctx)
+
+	maxEvents := 0
+	for _, opt := range opts {
+		if opt.maxEvents > 0 {
+			maxEvents = opt.maxEvents
+		}
+	}
+
+	var all []*Event
+	pageOpts := opts
+	for {
+		if err := ctx.Err(); err != nil {
+			return all, err
+		}
+
+		page, err := c.GetEvents(ctx, topic, subscription, pageOpts...)
+		if err != nil {
+			return all, err
+		}
+
+		if len(page.Events) == 0 {
+			return all, nil
+		}
+
+		all = append(all, page.Events...)
+		if maxEvents > 0 && len(all) >= maxEvents {
+			return all[:maxEvents], nil
+		}
+
+		pageOpts = append(opts[:len(opts):len(opts)], WithOffset(page.Offset+len(page.Events)))
+	}
+}
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// Field names used in publish request bodies.
+const (
+	FieldID       = "id"
+	FieldData     = "data"
+	FieldMetadata = "metadata"
+	FieldSendAt   = "send_at"
+	FieldHeaders  = "headers"
+)
+
+type publishConfig struct {
+	sendAt            *time.Time
+	allowPastSchedule bool
+	timeout           time.Duration
+	deliveryTimeout   time.Duration
+	idempotencyKey    string
+	eventID           string
+	caller            *callerInfo
+	err               error
+}
+
 type publishOpt struct {
-	mod func(data *map[string]any)
+	mod       func(data *map[string]any)
+	configure func(cfg *publishConfig)
 }
 
+// ScheduleSkew is how far in the past a scheduled time may be before Publish rejects it,
+// allowing for small clock differences.
+const ScheduleSkew = 5 * time.Second
+
+// ErrScheduledInPast is returned by Publish when WithScheduledTime is given a time in the past.
+var ErrScheduledInPast = errors.New("scheduled time is in the past")
+
 func WithScheduledTime(sendAt time.Time) publishOpt {
 	return publishOpt{
 		mod: func(data *map[string]any) {
 			timeString := sendAt.Format(time.RFC3339)
-			(*data)["send_at"] = timeString
+			(*data)[FieldSendAt] = timeString
+		},
+		configure: func(cfg *publishConfig) {
+			cfg.sendAt = &sendAt
+		},
+	}
+}
+
+// WithPublishTimeout bounds how long the publish may take, independent of the client's timeout.
+// The client's timeout still applies as an upper limit.
+func WithPublishTimeout(timeout time.Duration) publishOpt {
+	return publishOpt{
+		configure: func(cfg *publishConfig) {
+			cfg.timeout = timeout
+		},
+	}
+}
+
+// WithIdempotencyKey sets an Idempotency-Key header so the server can drop duplicate publishes.
+// The same key is sent on every retry of the publish, which also makes it safe to retry
+// without RetryNonIdempotent.
+func WithIdempotencyKey(key string) publishOpt {
+	return publishOpt{
+		configure: func(cfg *publishConfig) {
+			cfg.idempotencyKey = key
+		},
+	}
+}
+
+// WithEventID publishes the event with the given ID instead of one generated by the server.
+// Publishing a second event with the same ID fails with an error matching ErrDuplicateEvent.
+// PublishBatch rejects it with ErrEventIDInBatch, as every event would get the same ID.
+func WithEventID(id string) publishOpt {
+	return publishOpt{
+		mod: func(body *map[string]any) {
+			(*body)[FieldID] = id
+		},
+		configure: func(cfg *publishConfig) {
+			cfg.eventID = id
+		},
+	}
+}
+
+// AllowPastSchedule permits WithScheduledTime to be given a time in the past.
+func AllowPastSchedule() publishOpt {
+	return publishOpt{
+		configure: func(cfg *publishConfig) {
+			cfg.allowPastSchedule = true
 		},
 	}
 }
@@ -144,23 +519,112 @@ func WithScheduledTime(sendAt time.Time) publishOpt {
 func WithMetaData(data map[string]interface{}) publishOpt {
 	return publishOpt{
 		mod: func(body *map[string]any) {
-			(*body)["metadata"] = data
+			(*body)[FieldMetadata] = data
+		},
+	}
+}
+
+// WithMetaDataStruct sets the event's metadata from a struct, keyed by its JSON field names.
+// Publish fails if v doesn't encode to a JSON object.
+func WithMetaDataStruct(v any) publishOpt {
+	var metadata map[string]any
+	b, err := json.Marshal(v)
+	if err == nil {
+		err = json.Unmarshal(b, &metadata)
+	}
+	if err != nil {
+		err = fmt.Errorf("invalid metadata: %w", err)
+	}
+
+	return publishOpt{
+		mod: func(body *map[string]any) {
+			if err == nil {
+				(*body)[FieldMetadata] = metadata
+			}
+		},
+		configure: func(cfg *publishConfig) {
+			if err != nil && cfg.err == nil {
+				cfg.err = err
+			}
+		},
+	}
+}
+
+// WithHeaders sets transport headers on the event, such as routing keys or content type,
+// kept separate from its business metadata.
+func WithHeaders(headers map[string]string) publishOpt {
+	return publishOpt{
+		mod: func(body *map[string]any) {
+			(*body)[FieldHeaders] = headers
 		},
 	}
 }
 
+// applyPublishOpts applies the options to a request body and returns the resulting config.
+func applyPublishOpts(body map[string]any, opts []publishOpt) (publishConfig, error) {
+	var cfg publishConfig
+	for _, opt := range opts {
+		if opt.mod != nil {
+			opt.mod(&body)
+		}
+		if opt.configure != nil {
+			opt.configure(&cfg)
+		}
+	}
+
+	if cfg.err != nil {
+		return cfg, cfg.err
+	}
+
+	if cfg.sendAt != nil && !cfg.allowPastSchedule && cfg.sendAt.Before(time.Now().Add(-ScheduleSkew)) {
+		return cfg, fmt.Errorf("%w: %s", ErrScheduledInPast, cfg.sendAt.Format(time.RFC3339))
+	}
+
+	return cfg, nil
+}
+
 func (c *SailhouseClient) Publish(ctx context.Context, topic string, data interface{}, opts ...publishOpt) error {
-	endpoint := fmt.Sprintf("%s/topics/%s/events", BaseURL, topic)
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
+
+	if c.stampCaller {
+		opts = append(opts[:len(opts):len(opts)], withCaller(captureCaller(1)))
+	}
+
+	ctx, span := c.startSpan(ctx, "sailhouse.publish", "publish", trace.SpanKindProducer, topic, "")
+	start := time.Now()
+	err := c.publish(ctx, topic, data, opts...)
+	endSpan(span, err)
+	c.instruments.recordDuration(ctx, c.instruments.publishDuration, time.Since(start), "publish", topic, "")
+	c.instruments.recordResult(ctx, c.instruments.published, 1, err, "publish", topic, "")
+
+	return err
+}
+
+func (c *SailhouseClient) publish(ctx context.Context, topic string, data interface{}, opts ...publishOpt) error {
+	endpoint := fmt.Sprintf("%s/topics/%s/events", c.baseURL, topic)
 
 	body := map[string]interface{}{
-		"data": data,
+		FieldData: data,
 	}
 
-	for _, opt := range opts {
-		opt.mod(&body)
+	cfg, err := applyPublishOpts(body, opts)
+	if err != nil {
+		return err
 	}
 
-	jsonBody, err := json.Marshal(body)
+	if c.stampCaller {
+		stampCaller(body, cfg.caller)
+	}
+	c.injectTraceContext(ctx, body)
+
+	if cfg.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.timeout)
+		defer cancel()
+	}
+
+	jsonBody, err := json.Marshal(c.renameFields(body))
 	if err != nil {
 		return err
 	}
@@ -171,168 +635,130 @@ func (c *SailhouseClient) Publish(ctx context.Context, topic string, data interf
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	if cfg.idempotencyKey != "" {
+		req.Header.Set(idempotencyKeyHeader, cfg.idempotencyKey)
+	}
 
-	res, err := c.do(req)
+	res, err := c.doPublish("publish", req)
 	if err != nil {
-		return err
+		return opError("publish", topic, "", err)
 	}
 
+	defer res.Body.Close()
+
 	if res.StatusCode != 201 {
-		resText := ""
-		defer res.Body.Close()
+		return newAPIError("publish", topic, "", res)
+	}
 
-		b, err := io.ReadAll(res.Body)
+	if cfg.deliveryTimeout > 0 {
+		var published PublishResponse
+		err = json.NewDecoder(res.Body).Decode(&published)
 		if err != nil {
-			return err
+			return opError("publish", topic, "", err)
 		}
 
-		resText = string(b)
-		return fmt.Errorf("failed to send message: %d - %s", res.StatusCode, resText)
+		return c.confirmDelivery(ctx, topic, published.ID, cfg.deliveryTimeout)
 	}
 
 	return nil
 }
 
-func (c *SailhouseClient) AcknowledgeMessage(ctx context.Context, topic string, subscription string, id string) error {
-	endpoint := fmt.Sprintf("%s/topics/%s/subscriptions/%s/events/%s", BaseURL, topic, subscription, id)
-
-	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, nil)
-	if err != nil {
-		return err
+// renameFields applies any configured FieldNames overrides to a request body.
+func (c *SailhouseClient) renameFields(body map[string]any) map[string]any {
+	if len(c.fieldNames) == 0 {
+		return body
 	}
 
-	res, err := c.do(req)
-	if err != nil {
-		return err
+	renamed := make(map[string]any, len(body))
+	for k, v := range body {
+		if name, ok := c.fieldNames[k]; ok {
+			k = name
+		}
+		renamed[k] = v
 	}
 
-	if res.StatusCode != 200 && res.StatusCode != 204 {
-		return fmt.Errorf("failed to acknowledge message: %d", res.StatusCode)
-	}
+	return renamed
+}
 
-	return nil
+// AcknowledgeMessage acknowledges an event.
+//
+// Deprecated: use Ack.
+func (c *SailhouseClient) AcknowledgeMessage(ctx context.Context, topic string, subscription string, id string) error {
+	return c.Ack(ctx, topic, subscription, id)
 }
 
-func (c *SailhouseClient) StreamEvents(ctx context.Context, topic string, subscription string) (<-chan Event, <-chan error) {
-	done := ctx.Done()
-	events := make(chan Event)
-	errs := make(chan error)
+// Ack acknowledges an event by ID, marking it as processed for the subscription.
+func (c *SailhouseClient) Ack(ctx context.Context, topic string, subscription string, id string) error {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
 
-	messages := make(chan []byte)
+	ctx, span := c.startSpan(ctx, "sailhouse.ack", "ack", trace.SpanKindClient, topic, subscription)
+	span.SetAttributes(attribute.String("messaging.message.id", id))
+	err := c.ack(ctx, topic, subscription, id)
+	endSpan(span, err)
+	c.instruments.recordResult(ctx, c.instruments.acked, 1, err, "ack", topic, subscription)
 
-	u := url.URL{Scheme: "wss", Host: "api.sailhouse.dev", Path: "/events/stream"}
+	return err
+}
+
+func (c *SailhouseClient) ack(ctx context.Context, topic string, subscription string, id string) error {
+	endpoint := fmt.Sprintf("%s/topics/%s/subscriptions/%s/events/%s", c.baseURL, topic, subscription, id)
 
-	conn, _, err := websocket.DefaultDialer.DialContext(ctx, u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, nil)
 	if err != nil {
-		errs <- fmt.Errorf("failed to connect to websocket: %w", err)
-		return events, errs
+		return err
 	}
 
-	err = conn.WriteJSON(map[string]interface{}{
-		"topic_slug":        topic,
-		"subscription_slug": subscription,
-		"token":             c.token,
-	})
+	res, err := c.do("acknowledge", req)
 	if err != nil {
-		errs <- fmt.Errorf("failed to send auth message: %w", err)
-		return events, errs
+		return opError("acknowledge", topic, subscription, err)
 	}
 
-	go func() {
-		for {
-			_, message, err := conn.ReadMessage()
-			if err != nil {
-				if strings.Contains(err.Error(), "use of closed network connection") {
-					return
-				}
-				errs <- fmt.Errorf("failed to read message: %w", err)
-				return
-			}
+	if res.StatusCode != 200 && res.StatusCode != 204 {
+		return newAPIError("acknowledge", topic, subscription, res)
+	}
 
-			messages <- message
-		}
-	}()
-
-	go func() {
-		defer func() {
-			conn.Close()
-			close(messages)
-			close(errs)
-		}()
-
-		for {
-			select {
-			case <-done:
-				return
-			case message := <-messages:
-				var eventResponse EventResponse
-				err = json.Unmarshal(message, &eventResponse)
-				if err != nil {
-					errs <- fmt.Errorf("failed to unmarshal message: %w", err)
-					return
-				}
+	return nil
+}
 
-				event := Event{
-					ID:           eventResponse.ID,
-					Data:         eventResponse.Data,
-					topic:        topic,
-					subscription: subscription,
-					client:       c,
-				}
+// NackMessage negatively acknowledges an event.
+//
+// Deprecated: use Nack.
+func (c *SailhouseClient) NackMessage(ctx context.Context, topic string, subscription string, id string) error {
+	return c.Nack(ctx, topic, subscription, id)
+}
 
-				events <- event
-			}
-		}
-	}()
+// Nack negatively acknowledges an event by ID, asking the server to redeliver it later
+// rather than treating it as processed.
+func (c *SailhouseClient) Nack(ctx context.Context, topic string, subscription string, id string) error {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
 
-	return events, errs
-}
+	ctx, span := c.startSpan(ctx, "sailhouse.nack", "nack", trace.SpanKindClient, topic, subscription)
+	span.SetAttributes(attribute.String("messaging.message.id", id))
+	err := c.nack(ctx, topic, subscription, id)
+	endSpan(span, err)
+	c.instruments.recordResult(ctx, c.instruments.nacked, 1, err, "nack", topic, subscription)
 
-type SubscriptionOptions struct {
-	OnError   func(error)
-	ExitOnErr bool
+	return err
 }
 
-type SubscriptionHandler func(context.Context, *Event)
+func (c *SailhouseClient) nack(ctx context.Context, topic string, subscription string, id string) error {
+	endpoint := fmt.Sprintf("%s/topics/%s/subscriptions/%s/events/%s/nack", c.baseURL, topic, subscription, id)
 
-// Subscribe to a topic and subscription in the background, calling the handler function when new events are received.
-//
-// If an error is encountered, the `OnError` function within the SubscriptionOptions will be called.
-func (c *SailhouseClient) Subscribe(ctx context.Context, topic string, subscription string, handler SubscriptionHandler, opts *SubscriptionOptions) {
-	pollingInterval := 5 * time.Second
-	doneChan := ctx.Done()
-	errHandler := func(err error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, nil)
+	if err != nil {
+		return err
 	}
-	exitOnErr := false
 
-	if opts != nil {
-		if opts.OnError != nil {
-			errHandler = opts.OnError
-		}
+	res, err := c.do("nack", req)
+	if err != nil {
+		return opError("nack", topic, subscription, err)
+	}
 
-		exitOnErr = opts.ExitOnErr
-	}
-
-	go func() {
-		for {
-			select {
-			case <-time.After(pollingInterval):
-				events, err := c.GetEvents(ctx, topic, subscription)
-				if err != nil {
-					errHandler(err)
-					if exitOnErr {
-						break
-					} else {
-						continue
-					}
-				}
+	if res.StatusCode != 200 && res.StatusCode != 204 {
+		return newAPIError("nack", topic, subscription, res)
+	}
 
-				for _, event := range events.Events {
-					handler(ctx, event)
-				}
-			case <-doneChan:
-				return
-			}
-		}
-	}()
+	return nil
 }
@@ -0,0 +1,224 @@
+package sailhouse
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetEventsEmptyPolls(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		codes  []int
+	}{
+		{name: "default 204", status: http.StatusNoContent},
+		{name: "200 with empty body", status: http.StatusOK},
+		{name: "200 with empty body and custom codes", status: http.StatusOK, codes: []int{http.StatusNotFound, http.StatusNoContent}},
+		{name: "404 configured as empty", status: http.StatusNotFound, body: `{"message":"no events"}`, codes: []int{http.StatusNotFound, http.StatusNoContent}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}, func(o *SailhouseClientOptions) {
+				o.EmptyPollStatusCodes = tt.codes
+			})
+
+			res, err := client.GetEvents(context.Background(), "topic", "sub")
+			if err != nil {
+				t.Fatalf("expected an empty poll, got %v", err)
+			}
+			if len(res.Events) != 0 {
+				t.Errorf("expected no events, got %d", len(res.Events))
+			}
+		})
+	}
+}
+
+func TestGetEventsNotFoundIsErrorByDefault(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	if _, err := client.GetEvents(context.Background(), "topic", "sub"); err == nil {
+		t.Fatal("expected a 404 to fail without EmptyPollStatusCodes")
+	}
+}
+
+func TestGetEventsSendsConsumerInfo(t *testing.T) {
+	headers := make(chan string, 1)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		headers <- r.Header.Get("x-consumer-info")
+		w.Write([]byte(`{"events":[]}`))
+	}, func(o *SailhouseClientOptions) {
+		o.ConsumerInfo = map[string]string{"host": "worker-1", "version": "1.2.0"}
+	})
+
+	if _, err := client.GetEvents(context.Background(), "topic", "sub"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := <-headers; got != "host=worker-1&version=1.2.0" {
+		t.Errorf("unexpected consumer info header %q", got)
+	}
+}
+
+func TestGetEventsOmitsConsumerInfoByDefault(t *testing.T) {
+	headers := make(chan []string, 1)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		headers <- r.Header.Values("x-consumer-info")
+		w.Write([]byte(`{"events":[]}`))
+	})
+
+	if _, err := client.GetEvents(context.Background(), "topic", "sub"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := <-headers; len(got) != 0 {
+		t.Errorf("expected no consumer info header, got %q", got)
+	}
+}
+
+func TestResponseHookCalledForPublishAndPull(t *testing.T) {
+	type call struct {
+		op     string
+		status int
+		err    error
+	}
+	var calls []call
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		w.Write([]byte(`{"events":[]}`))
+	}, func(o *SailhouseClientOptions) {
+		o.ResponseHook = func(op string, req *http.Request, resp *http.Response, err error, dur time.Duration) {
+			c := call{op: op, err: err}
+			if resp != nil {
+				c.status = resp.StatusCode
+			}
+			calls = append(calls, c)
+		}
+	})
+
+	if err := client.Publish(context.Background(), "topic", "data"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetEvents(context.Background(), "topic", "sub"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []call{{op: "publish", status: http.StatusCreated}, {op: "get_events", status: http.StatusOK}}
+	if len(calls) != len(want) {
+		t.Fatalf("expected %d hook calls, got %+v", len(want), calls)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("call %d: expected %+v, got %+v", i, want[i], calls[i])
+		}
+	}
+}
+
+func TestResponseHookCalledOnFailure(t *testing.T) {
+	var hookErr error
+	client := NewSailhouseClientWithOptions(SailhouseClientOptions{
+		Token:   "token",
+		BaseURL: "http://127.0.0.1:1",
+		ResponseHook: func(op string, req *http.Request, resp *http.Response, err error, dur time.Duration) {
+			hookErr = err
+		},
+	})
+
+	if _, err := client.GetEvents(context.Background(), "topic", "sub"); err == nil {
+		t.Fatal("expected the pull to fail")
+	}
+	if hookErr == nil {
+		t.Error("expected the hook to receive the error")
+	}
+}
+
+func TestPathPrefixedBaseURL(t *testing.T) {
+	paths := make(chan string, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.Path
+		switch {
+		case r.Method == http.MethodGet:
+			w.Write([]byte(`{"events":[]}`))
+		case strings.HasSuffix(r.URL.Path, "/topics/orders/events"):
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client := NewSailhouseClientWithOptions(SailhouseClientOptions{Token: "token", BaseURL: srv.URL + "/api/"})
+	ctx := context.Background()
+
+	calls := []struct {
+		name string
+		call func() error
+		want string
+	}{
+		{"publish", func() error { return client.Publish(ctx, "orders", "data") }, "/api/topics/orders/events"},
+		{"pull", func() error { _, err := client.GetEvents(ctx, "orders", "billing"); return err }, "/api/topics/orders/subscriptions/billing/events"},
+		{"ack", func() error { return client.Ack(ctx, "orders", "billing", "e1") }, "/api/topics/orders/subscriptions/billing/events/e1"},
+		{"nack", func() error { return client.Nack(ctx, "orders", "billing", "e1") }, "/api/topics/orders/subscriptions/billing/events/e1/nack"},
+	}
+
+	for _, c := range calls {
+		if err := c.call(); err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if got := <-paths; got != c.want {
+			t.Errorf("%s: expected path %s, got %s", c.name, c.want, got)
+		}
+	}
+
+	streamURL, err := client.streamURL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/events/stream"; streamURL != want {
+		t.Errorf("expected stream URL %s, got %s", want, streamURL)
+	}
+}
+
+func TestWithResponseTimeoutOutlivesClientTimeout(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(150 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		w.Write([]byte(`{"events":[{"id":"e1","data":{}}]}`))
+	}, func(o *SailhouseClientOptions) {
+		o.Client = &http.Client{Timeout: 50 * time.Millisecond}
+	})
+	ctx := context.Background()
+
+	if _, err := client.GetEvents(ctx, "topic", "sub"); err == nil {
+		t.Error("expected the client's timeout to cut the pull short")
+	}
+
+	res, err := client.GetEvents(ctx, "topic", "sub", WithResponseTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("expected the pull to outlive the client's timeout, got %v", err)
+	}
+	if len(res.Events) != 1 {
+		t.Errorf("expected the held event, got %v", res.Events)
+	}
+
+	if _, err := client.GetEvents(ctx, "topic", "sub", WithResponseTimeout(100*time.Millisecond)); err == nil {
+		t.Error("expected the response timeout to still bound the pull")
+	}
+}
@@ -0,0 +1,27 @@
+package sailhouse
+
+import "context"
+
+// requestContext defaults a nil context for single requests to one bounded by the client's
+// timeout, rather than panicking.
+func (c *SailhouseClient) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx != nil {
+		return ctx, func() {}
+	}
+
+	if c.client.Timeout > 0 {
+		return context.WithTimeout(context.Background(), c.client.Timeout)
+	}
+
+	return context.Background(), func() {}
+}
+
+// orBackground defaults a nil context for long-running operations, such as streams and
+// subscriptions, which shouldn't be bounded by a request timeout.
+func orBackground(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+
+	return ctx
+}
@@ -0,0 +1,53 @@
+package sailhouse
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNilContextDefaults(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(`{"events":[{"id":"e1","data":{}}]}`))
+		default:
+			w.WriteHeader(http.StatusCreated)
+		}
+	})
+
+	if err := client.Publish(nil, "topic", "data"); err != nil {
+		t.Errorf("publish: %v", err)
+	}
+	if _, err := client.GetEvents(nil, "topic", "sub"); err != nil {
+		t.Errorf("get events: %v", err)
+	}
+
+	handled := make(chan *Event, 1)
+	sub := client.Subscribe(nil, "topic", "sub", func(ctx context.Context, e *Event) {
+		select {
+		case handled <- e:
+		default:
+		}
+	}, &SubscriptionOptions{PollInterval: time.Millisecond})
+	defer sub.Stop()
+
+	select {
+	case <-handled:
+	case <-time.After(time.Second):
+		t.Fatal("expected the subscription to run with a nil context")
+	}
+}
+
+func TestNilContextUsesClientTimeout(t *testing.T) {
+	client := NewSailhouseClientWithOptions(SailhouseClientOptions{Token: "token", Client: &http.Client{Timeout: time.Minute}})
+
+	ctx, cancel := client.requestContext(nil)
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	if !ok || time.Until(deadline) > time.Minute {
+		t.Errorf("expected a deadline within the client timeout, got %v", deadline)
+	}
+}
@@ -0,0 +1,83 @@
+package sailhouse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrDeliveryUnconfirmed is returned by Publish when WithDeliveryConfirmation is set and no
+// subscriber received the event within the timeout.
+var ErrDeliveryUnconfirmed = errors.New("event delivery not confirmed")
+
+// deliveryPollInterval is how often the delivery status is checked while confirming delivery.
+const deliveryPollInterval = 250 * time.Millisecond
+
+// WithDeliveryConfirmation makes Publish wait until at least one subscriber has received the
+// event, returning ErrDeliveryUnconfirmed if that doesn't happen within the timeout.
+//
+// Publish polls the event's delivery status after it's accepted, so it takes at least as long
+// as delivery does. Any WithPublishTimeout or context deadline also bounds the wait.
+func WithDeliveryConfirmation(timeout time.Duration) publishOpt {
+	return publishOpt{
+		configure: func(cfg *publishConfig) {
+			cfg.deliveryTimeout = timeout
+		},
+	}
+}
+
+type deliveryStatus struct {
+	Delivered bool `json:"delivered"`
+}
+
+func (c *SailhouseClient) confirmDelivery(ctx context.Context, topic, id string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	endpoint := fmt.Sprintf("%s/topics/%s/events/%s/status", c.baseURL, topic, id)
+
+	for {
+		delivered, err := c.deliveryStatus(ctx, endpoint, topic)
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+
+		if delivered {
+			return nil
+		}
+
+		select {
+		case <-time.After(deliveryPollInterval):
+		case <-ctx.Done():
+			return opError("confirm_delivery", topic, "", fmt.Errorf("%w: event %s", ErrDeliveryUnconfirmed, id))
+		}
+	}
+}
+
+func (c *SailhouseClient) deliveryStatus(ctx context.Context, endpoint, topic string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return false, err
+	}
+
+	res, err := c.do("confirm_delivery", req)
+	if err != nil {
+		return false, opError("confirm_delivery", topic, "", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return false, newAPIError("confirm_delivery", topic, "", res)
+	}
+
+	var status deliveryStatus
+	err = json.NewDecoder(res.Body).Decode(&status)
+	if err != nil {
+		return false, opError("confirm_delivery", topic, "", err)
+	}
+
+	return status.Delivered, nil
+}
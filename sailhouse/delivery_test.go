@@ -0,0 +1,62 @@
+package sailhouse
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// deliveryServer accepts publishes as event e1 and reports it delivered from the given status
+// check onwards, or never when deliveredFrom is zero.
+func deliveryServer(t *testing.T, deliveredFrom int32) (*SailhouseClient, *int32) {
+	t.Helper()
+
+	var checks int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"e1"}`))
+			return
+		}
+
+		if r.URL.Path != "/topics/topic/events/e1/status" {
+			t.Errorf("unexpected status path %s", r.URL.Path)
+		}
+		n := atomic.AddInt32(&checks, 1)
+		if deliveredFrom > 0 && n >= deliveredFrom {
+			w.Write([]byte(`{"delivered":true}`))
+			return
+		}
+		w.Write([]byte(`{"delivered":false}`))
+	})
+
+	return client, &checks
+}
+
+func TestPublishDeliveryConfirmed(t *testing.T) {
+	client, checks := deliveryServer(t, 2)
+
+	err := client.Publish(context.Background(), "topic", "data", WithDeliveryConfirmation(time.Second))
+	if err != nil {
+		t.Fatalf("expected delivery to be confirmed, got %v", err)
+	}
+	if n := atomic.LoadInt32(checks); n != 2 {
+		t.Errorf("expected the status to be polled until delivered, got %d checks", n)
+	}
+}
+
+func TestPublishDeliveryTimedOut(t *testing.T) {
+	client, _ := deliveryServer(t, 0)
+
+	start := time.Now()
+	err := client.Publish(context.Background(), "topic", "data", WithDeliveryConfirmation(100*time.Millisecond))
+	if !errors.Is(err, ErrDeliveryUnconfirmed) {
+		t.Fatalf("expected ErrDeliveryUnconfirmed, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected to give up at the timeout, took %v", elapsed)
+	}
+}
@@ -0,0 +1,169 @@
+package sailhouse
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrRateLimited  = errors.New("rate limited")
+	// ErrDuplicateEvent matches a publish rejected because an event with the ID given by
+	// WithEventID already exists. The earlier publish succeeded, so it's usually safe to ignore.
+	ErrDuplicateEvent = errors.New("duplicate event")
+)
+
+// SailhouseAPIError is returned when the API responds with an unexpected status code.
+//
+// It matches ErrUnauthorized, ErrForbidden, ErrNotFound, ErrRateLimited and ErrDuplicateEvent
+// with errors.Is based on the status code.
+type SailhouseAPIError struct {
+	// Op is the operation that failed, e.g. "publish".
+	Op           string
+	Topic        string
+	Subscription string
+	StatusCode   int
+	Message      string
+	RequestID    string
+}
+
+func (e *SailhouseAPIError) Error() string {
+	msg := fmt.Sprintf("%s failed: %d", describeOp(e.Op, e.Topic, e.Subscription), e.StatusCode)
+	if e.Message != "" {
+		msg += " - " + e.Message
+	}
+	if e.RequestID != "" {
+		msg += fmt.Sprintf(" (request %s)", e.RequestID)
+	}
+
+	return msg
+}
+
+func (e *SailhouseAPIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrDuplicateEvent:
+		return e.StatusCode == http.StatusConflict
+	}
+
+	return false
+}
+
+// describeOp names an operation along with the topic and subscription it acted on.
+func describeOp(op, topic, subscription string) string {
+	switch {
+	case topic != "" && subscription != "":
+		return fmt.Sprintf("%s (topic %q, subscription %q)", op, topic, subscription)
+	case topic != "":
+		return fmt.Sprintf("%s (topic %q)", op, topic)
+	}
+
+	return op
+}
+
+// opError adds the operation, topic and subscription to errors that didn't come from the
+// API itself, such as network failures.
+func opError(op, topic, subscription string, err error) error {
+	return fmt.Errorf("%s failed: %w", describeOp(op, topic, subscription), err)
+}
+
+// newAPIError builds an error from an unexpected response, consuming and closing its body.
+// Responses that were retried until the policy gave up are wrapped in a *RetryError.
+func newAPIError(op, topic, subscription string, res *http.Response) error {
+	apiErr := readAPIError(op, topic, subscription, res)
+	if retried, ok := res.Body.(*retriedBody); ok {
+		return &RetryError{Attempts: retried.attempts, Err: apiErr}
+	}
+
+	return apiErr
+}
+
+func readAPIError(op, topic, subscription string, res *http.Response) *SailhouseAPIError {
+	defer res.Body.Close()
+
+	apiErr := &SailhouseAPIError{
+		Op:           op,
+		Topic:        topic,
+		Subscription: subscription,
+		StatusCode:   res.StatusCode,
+		RequestID:    res.Header.Get("x-request-id"),
+	}
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil || len(b) == 0 {
+		return apiErr
+	}
+
+	var body struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if json.Unmarshal(b, &body) == nil && (body.Message != "" || body.Error != "") {
+		apiErr.Message = body.Message
+		if apiErr.Message == "" {
+			apiErr.Message = body.Error
+		}
+		return apiErr
+	}
+
+	apiErr.Message = strings.TrimSpace(string(b))
+	return apiErr
+}
+
+// rateLimitErrors wraps an error handler so that an error identical to the last one reported
+// is passed on at most once per interval. The number of errors suppressed in between is
+// added to the next one that's reported, or reported on its own before a different error.
+func rateLimitErrors(handler func(error), interval time.Duration) func(error) {
+	var (
+		mu         sync.Mutex
+		last       string
+		lastErr    error
+		lastReport time.Time
+		suppressed int
+	)
+
+	return func(err error) {
+		mu.Lock()
+		msg := err.Error()
+		if msg == last && time.Since(lastReport) < interval {
+			suppressed++
+			mu.Unlock()
+			return
+		}
+
+		var pending error
+		if suppressed > 0 {
+			if msg == last {
+				err = fmt.Errorf("%w (%d similar errors suppressed)", err, suppressed)
+			} else {
+				pending = fmt.Errorf("%w (%d similar errors suppressed)", lastErr, suppressed)
+			}
+		}
+
+		last = msg
+		lastErr = err
+		lastReport = time.Now()
+		suppressed = 0
+		mu.Unlock()
+
+		if pending != nil {
+			handler(pending)
+		}
+		handler(err)
+	}
+}
@@ -0,0 +1,112 @@
+package sailhouse
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimitErrorsSuppressesRepeats(t *testing.T) {
+	var reported []error
+	handler := rateLimitErrors(func(err error) { reported = append(reported, err) }, 20*time.Millisecond)
+
+	boom := errors.New("boom")
+	handler(boom)
+	handler(boom)
+	handler(boom)
+
+	if len(reported) != 1 {
+		t.Fatalf("expected repeats to be suppressed, got %v", reported)
+	}
+
+	time.Sleep(25 * time.Millisecond)
+	handler(boom)
+
+	if len(reported) != 2 {
+		t.Fatalf("expected the error once the interval passed, got %v", reported)
+	}
+	if !strings.Contains(reported[1].Error(), "2 similar errors suppressed") {
+		t.Errorf("expected the suppressed count, got %q", reported[1])
+	}
+	if !errors.Is(reported[1], boom) {
+		t.Errorf("expected the reported error to wrap the original")
+	}
+}
+
+func TestRateLimitErrorsReportsCountBeforeNewError(t *testing.T) {
+	var reported []error
+	handler := rateLimitErrors(func(err error) { reported = append(reported, err) }, time.Hour)
+
+	first := errors.New("first")
+	second := errors.New("second")
+	handler(first)
+	handler(first)
+	handler(first)
+	handler(second)
+
+	if len(reported) != 3 {
+		t.Fatalf("expected first, its suppressed count and second, got %v", reported)
+	}
+	if !errors.Is(reported[1], first) || !strings.Contains(reported[1].Error(), "2 similar errors suppressed") {
+		t.Errorf("expected the suppressed count of the first error, got %q", reported[1])
+	}
+	if reported[2] != second {
+		t.Errorf("expected the new error last, got %q", reported[2])
+	}
+}
+
+func TestRateLimitErrorsPassesDistinctErrors(t *testing.T) {
+	var reported []error
+	handler := rateLimitErrors(func(err error) { reported = append(reported, err) }, time.Hour)
+
+	handler(errors.New("a"))
+	handler(errors.New("b"))
+	handler(errors.New("a"))
+
+	if len(reported) != 3 {
+		t.Fatalf("expected alternating errors to all be reported, got %v", reported)
+	}
+}
+
+func TestAPIErrorIncludesOperationContext(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-request-id", "req-1")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message":"bad subscription"}`))
+	})
+
+	_, err := client.GetEvents(context.Background(), "orders", "billing")
+
+	var apiErr *SailhouseAPIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an API error, got %v", err)
+	}
+	if apiErr.Op != "get_events" || apiErr.Topic != "orders" || apiErr.Subscription != "billing" {
+		t.Errorf("expected the operation, topic and subscription, got %+v", apiErr)
+	}
+
+	for _, want := range []string{`topic "orders"`, `subscription "billing"`, "400", "bad subscription", "req-1"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %q", want, err)
+		}
+	}
+}
+
+func TestNetworkErrorIncludesOperationContext(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	client := NewSailhouseClientWithOptions(SailhouseClientOptions{Token: "token", BaseURL: srv.URL})
+
+	err := client.Publish(context.Background(), "orders", map[string]string{"a": "b"})
+	if err == nil {
+		t.Fatal("expected an error from the closed server")
+	}
+
+	if !strings.Contains(err.Error(), `publish (topic "orders")`) {
+		t.Errorf("expected the operation and topic, got %q", err)
+	}
+}
@@ -3,6 +3,9 @@ package sailhouse
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
 )
 
 type GetEventsResponse struct {
@@ -11,20 +14,86 @@ type GetEventsResponse struct {
 	Limit  int      `json:"limit"`
 }
 
+// AckAll acknowledges every event in the response. Failures on individual
+// events don't stop the remaining acks; all errors are joined and returned.
+func (r GetEventsResponse) AckAll(ctx context.Context) error {
+	var errs []error
+	for _, e := range r.Events {
+		if err := e.Ack(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to ack event %s: %w", e.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 type EventResponse struct {
 	ID   string                 `json:"id"`
 	Data map[string]interface{} `json:"data"`
 }
 
 type Event struct {
-	ID           string                 `json:"id"`
-	Data         map[string]interface{} `json:"data"`
+	ID      string                 `json:"id"`
+	Data    map[string]interface{} `json:"data"`
+	Headers map[string]string      `json:"headers,omitempty"`
+	// Metadata is the metadata the event was published with.
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	// Raw holds the undecoded JSON of the event's data, when the event was received from the API.
+	Raw          json.RawMessage `json:"-"`
 	topic        string
 	subscription string
 	client       *SailhouseClient
+	stream       *eventStream
+	conn         *streamConn
+	acked        int32
+}
+
+// UnmarshalJSON decodes an event, keeping the raw bytes of its data alongside the decoded map.
+func (e *Event) UnmarshalJSON(b []byte) error {
+	var event struct {
+		ID       string                 `json:"id"`
+		Data     json.RawMessage        `json:"data"`
+		Headers  map[string]string      `json:"headers"`
+		Metadata map[string]interface{} `json:"metadata"`
+	}
+
+	err := json.Unmarshal(b, &event)
+	if err != nil {
+		return err
+	}
+
+	e.ID = event.ID
+	e.Data = nil
+	e.Raw = event.Data
+	e.Headers = event.Headers
+	e.Metadata = event.Metadata
+
+	if len(event.Data) > 0 {
+		return json.Unmarshal(event.Data, &e.Data)
+	}
+
+	return nil
+}
+
+// ErrNoData is returned by As when the event's data is missing or null.
+var ErrNoData = errors.New("event has no data")
+
+// HasData reports whether the event carries data, rather than a missing or null data field.
+func (e *Event) HasData() bool {
+	return e.Data != nil || (len(e.Raw) > 0 && string(e.Raw) != "null")
 }
 
+// As decodes the event's data into the value pointed to by data. Raw is used when available,
+// so numbers keep their full precision. ErrNoData is returned when the event has no data.
 func (e *Event) As(data any) error {
+	if !e.HasData() {
+		return ErrNoData
+	}
+
+	if len(e.Raw) > 0 {
+		return json.Unmarshal(e.Raw, data)
+	}
+
 	dataBytes, err := json.Marshal(e.Data)
 	if err != nil {
 		return err
@@ -38,6 +107,74 @@ func (e *Event) As(data any) error {
 	return nil
 }
 
+// DecodeEvent decodes the event's data into a new value of type T.
+//
+//	user, err := sailhouse.DecodeEvent[UserCreated](event)
+func DecodeEvent[T any](e *Event) (T, error) {
+	var data T
+	if err := e.As(&data); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return data, nil
+}
+
+// Topic returns the topic the event was received from, e.g. to tell sources apart in a
+// handler registered with SubscribeAll.
+func (e *Event) Topic() string {
+	return e.topic
+}
+
+// Subscription returns the subscription the event was received on.
+func (e *Event) Subscription() string {
+	return e.subscription
+}
+
 func (e *Event) Ack(ctx context.Context) error {
-	return e.client.AcknowledgeMessage(ctx, e.topic, e.subscription, e.ID)
+	err := e.client.Ack(ctx, e.topic, e.subscription, e.ID)
+	if err == nil {
+		atomic.StoreInt32(&e.acked, 1)
+		if e.stream != nil {
+			e.stream.markAcked(e.ID)
+		}
+	}
+
+	return err
+}
+
+// ErrNotStreamed is returned by AckOverStream for events that weren't received from a stream.
+var ErrNotStreamed = errors.New("event was not received from a stream")
+
+// AckOverStream acknowledges a streamed event by writing an ack message on the websocket
+// connection it arrived on, avoiding a separate HTTP request. It fails if that connection has
+// since closed, in which case Ack can be used instead.
+func (e *Event) AckOverStream(ctx context.Context) error {
+	if e.conn == nil {
+		return ErrNotStreamed
+	}
+
+	err := e.conn.writeJSON(orBackground(ctx), map[string]interface{}{
+		"type":     "ack",
+		"event_id": e.ID,
+	})
+	if err != nil {
+		return opError("stream_ack", e.topic, e.subscription, err)
+	}
+
+	atomic.StoreInt32(&e.acked, 1)
+	e.stream.markAcked(e.ID)
+
+	return nil
+}
+
+// Nack tells the server the event wasn't processed, so it's redelivered to the subscription
+// later instead of waiting for the acknowledgement to time out. Like Ack, the request is
+// cancelled with the context.
+func (e *Event) Nack(ctx context.Context) error {
+	return e.client.Nack(ctx, e.topic, e.subscription, e.ID)
+}
+
+func (e *Event) isAcked() bool {
+	return atomic.LoadInt32(&e.acked) == 1
 }
@@ -0,0 +1,165 @@
+package sailhouse
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestEventMissingData(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"events":[{"id":"null","data":null},{"id":"missing"},{"id":"present","data":{"name":"a"}}]}`))
+	})
+
+	res, err := client.GetEvents(context.Background(), "topic", "sub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Events) != 3 {
+		t.Fatalf("expected three events, got %d", len(res.Events))
+	}
+
+	for _, event := range res.Events[:2] {
+		t.Run(event.ID, func(t *testing.T) {
+			if event.HasData() {
+				t.Error("expected no data")
+			}
+
+			data := struct{ Name string }{Name: "unchanged"}
+			if err := event.As(&data); !errors.Is(err, ErrNoData) {
+				t.Errorf("expected ErrNoData, got %v", err)
+			}
+			if data.Name != "unchanged" {
+				t.Errorf("expected the target to be left alone, got %q", data.Name)
+			}
+		})
+	}
+
+	t.Run("present", func(t *testing.T) {
+		event := res.Events[2]
+		if !event.HasData() {
+			t.Error("expected data")
+		}
+
+		var data struct{ Name string }
+		if err := event.As(&data); err != nil || data.Name != "a" {
+			t.Errorf("expected the data to decode, got %q and %v", data.Name, err)
+		}
+	})
+}
+
+func TestEventSource(t *testing.T) {
+	t.Run("pulled", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"events":[{"id":"e1","data":{}}]}`))
+		})
+
+		res, err := client.GetEvents(context.Background(), "orders", "billing")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		event := res.Events[0]
+		if event.Topic() != "orders" || event.Subscription() != "billing" {
+			t.Errorf("expected orders/billing, got %s/%s", event.Topic(), event.Subscription())
+		}
+	})
+
+	t.Run("streamed", func(t *testing.T) {
+		client := newStreamClient(t, func(conn *websocket.Conn) {
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"id":"e1","data":{}}`))
+			conn.ReadMessage()
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		events, _ := client.StreamEvents(ctx, "orders", "billing")
+		event, ok := <-events
+		if !ok {
+			t.Fatal("expected a streamed event")
+		}
+		if event.Topic() != "orders" || event.Subscription() != "billing" {
+			t.Errorf("expected orders/billing, got %s/%s", event.Topic(), event.Subscription())
+		}
+	})
+}
+
+func TestAckAll(t *testing.T) {
+	acked := make(chan string, 3)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"events":[{"id":"e1","data":{}},{"id":"e2","data":{}},{"id":"e3","data":{}}]}`))
+			return
+		}
+
+		id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		acked <- id
+		if id == "e2" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	res, err := client.GetEvents(context.Background(), "topic", "sub")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = res.AckAll(context.Background())
+	close(acked)
+
+	var got []string
+	for id := range acked {
+		got = append(got, id)
+	}
+	if strings.Join(got, ",") != "e1,e2,e3" {
+		t.Errorf("expected every event to be acked past the failure, got %v", got)
+	}
+
+	var apiErr *SailhouseAPIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected the failed ack's API error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "e2") || strings.Contains(err.Error(), "e1") || strings.Contains(err.Error(), "e3") {
+		t.Errorf("expected only e2 to be reported, got %q", err)
+	}
+	if !res.Events[0].isAcked() || res.Events[1].isAcked() || !res.Events[2].isAcked() {
+		t.Error("expected only the successful acks to be recorded")
+	}
+}
+
+func TestEventAsKeepsNumberPrecision(t *testing.T) {
+	// 2^53 + 1 can't be represented exactly as a float64
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"events":[{"id":"e1","data":{"user_id":9007199254740993}}]}`))
+	})
+
+	res, err := client.GetEvents(context.Background(), "topic", "sub")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var data struct {
+		UserID int64 `json:"user_id"`
+	}
+	if err := res.Events[0].As(&data); err != nil {
+		t.Fatal(err)
+	}
+	if data.UserID != 9007199254740993 {
+		t.Errorf("expected the ID to survive decoding, got %d", data.UserID)
+	}
+
+	decoded, err := DecodeEvent[struct {
+		UserID int64 `json:"user_id"`
+	}](res.Events[0])
+	if err != nil || decoded.UserID != 9007199254740993 {
+		t.Errorf("expected DecodeEvent to keep the ID too, got %d and %v", decoded.UserID, err)
+	}
+}
@@ -0,0 +1,59 @@
+package sailhouse
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+)
+
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (b *gzipBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
+}
+
+// decompress transparently decodes gzip-encoded response bodies. The standard transport
+// already does this unless compression is disabled or a custom transport is used. Responses
+// without a body are left as they are, even if they claim to be gzip-encoded.
+func decompress(res *http.Response) error {
+	if res.Header.Get("Content-Encoding") != "gzip" || !hasBody(res) {
+		return nil
+	}
+
+	reader, err := gzip.NewReader(res.Body)
+	if errors.Is(err, io.EOF) {
+		// An empty body of unknown length
+		res.Header.Del("Content-Encoding")
+		return nil
+	}
+	if err != nil {
+		res.Body.Close()
+		return err
+	}
+
+	res.Body = &gzipBody{Reader: reader, body: res.Body}
+	res.Header.Del("Content-Encoding")
+	res.Header.Del("Content-Length")
+	res.ContentLength = -1
+	res.Uncompressed = true
+
+	return nil
+}
+
+func hasBody(res *http.Response) bool {
+	if res.Request != nil && res.Request.Method == http.MethodHead {
+		return false
+	}
+
+	switch res.StatusCode {
+	case http.StatusNoContent, http.StatusNotModified:
+		return false
+	}
+
+	return res.ContentLength != 0
+}
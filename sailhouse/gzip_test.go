@@ -0,0 +1,98 @@
+package sailhouse
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func gzipHandler(status int, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(status)
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte(body))
+		gz.Close()
+	}
+}
+
+func withoutTransportCompression(o *SailhouseClientOptions) {
+	o.Client = &http.Client{Transport: &http.Transport{DisableCompression: true}}
+}
+
+func TestGetEventsDecompressesGzip(t *testing.T) {
+	client := newTestClient(t, gzipHandler(http.StatusOK, `{"events":[{"id":"e1","data":{"a":1}}]}`), withoutTransportCompression)
+
+	res, err := client.GetEvents(context.Background(), "topic", "sub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Events) != 1 || res.Events[0].ID != "e1" {
+		t.Errorf("expected the gzipped event, got %+v", res.Events)
+	}
+}
+
+func TestAPIErrorDecompressesGzip(t *testing.T) {
+	client := newTestClient(t, gzipHandler(http.StatusBadRequest, `{"message":"bad topic"}`), withoutTransportCompression)
+
+	_, err := client.GetEvents(context.Background(), "topic", "sub")
+
+	var apiErr *SailhouseAPIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an API error, got %v", err)
+	}
+	if apiErr.Message != "bad topic" {
+		t.Errorf("expected the decompressed message, got %q", apiErr.Message)
+	}
+}
+
+func TestInvalidGzipFails(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write([]byte("not gzip"))
+	}, withoutTransportCompression)
+
+	if _, err := client.GetEvents(context.Background(), "topic", "sub"); err == nil {
+		t.Fatal("expected an error for an invalid gzip body")
+	}
+}
+
+func TestEmptyGzipResponses(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		header func(h http.Header)
+	}{
+		{name: "no content", status: http.StatusNoContent},
+		{name: "zero length", status: http.StatusOK, header: func(h http.Header) { h.Set("Content-Length", "0") }},
+		// Without a length the response is chunked, so the body is only found empty once read
+		{name: "unknown length", status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Encoding", "gzip")
+				if tt.header != nil {
+					tt.header(w.Header())
+				}
+				w.WriteHeader(tt.status)
+				w.(http.Flusher).Flush()
+			}, withoutTransportCompression)
+
+			if err := client.Ack(context.Background(), "topic", "sub", "e1"); err != nil {
+				t.Errorf("expected the ack to succeed, got %v", err)
+			}
+
+			res, err := client.GetEvents(context.Background(), "topic", "sub")
+			if err != nil {
+				t.Fatalf("expected an empty poll, got %v", err)
+			}
+			if len(res.Events) != 0 {
+				t.Errorf("expected no events, got %v", res.Events)
+			}
+		})
+	}
+}
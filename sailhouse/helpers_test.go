@@ -0,0 +1,46 @@
+package sailhouse
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestClient starts a server with the handler and returns a client pointed at it.
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...func(*SailhouseClientOptions)) *SailhouseClient {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	options := SailhouseClientOptions{Token: "token", BaseURL: srv.URL}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return NewSailhouseClientWithOptions(options)
+}
+
+// recordingBackoff records the attempts it's asked for and how often it's reset.
+type recordingBackoff struct {
+	mu       sync.Mutex
+	attempts []int
+	resets   int
+}
+
+func (b *recordingBackoff) NextDelay(attempt int) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.attempts = append(b.attempts, attempt)
+	return time.Millisecond
+}
+
+func (b *recordingBackoff) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.resets++
+}
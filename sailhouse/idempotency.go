@@ -0,0 +1,72 @@
+package sailhouse
+
+import (
+	"context"
+	"sync"
+)
+
+// IdempotencyStore remembers which events have been processed, so Subscribe can skip events
+// that are delivered more than once.
+//
+// A store shared between processes makes processing effectively-once across all of them.
+// With Redis, for example:
+//
+//	func (s RedisStore) Seen(ctx context.Context, id string) (bool, error) {
+//		n, err := s.rdb.Exists(ctx, "sailhouse:processed:"+id).Result()
+//		return n > 0, err
+//	}
+//
+//	func (s RedisStore) Mark(ctx context.Context, id string) error {
+//		return s.rdb.Set(ctx, "sailhouse:processed:"+id, 1, 24*time.Hour).Err()
+//	}
+type IdempotencyStore interface {
+	// Seen reports whether the event has already been processed.
+	Seen(ctx context.Context, id string) (bool, error)
+	// Mark records the event as processed.
+	Mark(ctx context.Context, id string) error
+}
+
+// MemoryIdempotencyStore is an IdempotencyStore for a single process.
+type MemoryIdempotencyStore struct {
+	max int
+
+	mu    sync.Mutex
+	seen  map[string]struct{}
+	order []string
+}
+
+// NewMemoryIdempotencyStore creates an in-memory store remembering up to max event IDs,
+// forgetting the oldest first. Unlimited when max is zero.
+func NewMemoryIdempotencyStore(max int) *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		max:  max,
+		seen: map[string]struct{}{},
+	}
+}
+
+func (s *MemoryIdempotencyStore) Seen(_ context.Context, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.seen[id]
+	return ok, nil
+}
+
+func (s *MemoryIdempotencyStore) Mark(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.seen[id]; ok {
+		return nil
+	}
+
+	if s.max > 0 && len(s.order) >= s.max {
+		delete(s.seen, s.order[0])
+		s.order = s.order[1:]
+	}
+
+	s.seen[id] = struct{}{}
+	s.order = append(s.order, id)
+
+	return nil
+}
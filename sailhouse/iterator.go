@@ -0,0 +1,111 @@
+package sailhouse
+
+import "context"
+
+// EventsIterator lazily pages through the events of a subscription.
+//
+//	it := client.EventsIterator(ctx, "topic", "subscription")
+//	defer it.Close()
+//	for it.Next() {
+//		event := it.Event()
+//	}
+//	if err := it.Err(); err != nil {
+//		// handle error
+//	}
+type EventsIterator struct {
+	client       *SailhouseClient
+	parent       context.Context
+	ctx          context.Context
+	cancel       context.CancelFunc
+	topic        string
+	subscription string
+	opts         []getOption
+
+	page    []*Event
+	index   int
+	offset  int
+	fetched bool
+	current *Event
+	done    bool
+	err     error
+}
+
+// EventsIterator returns an iterator over the events of a subscription, fetching pages with
+// GetEvents as they're needed. Close should be called once the iterator is no longer used.
+func (c *SailhouseClient) EventsIterator(ctx context.Context, topic, subscription string, opts ...getOption) *EventsIterator {
+	ctx = orBackground(ctx)
+
+	iterCtx, cancel := context.WithCancel(ctx)
+
+	return &EventsIterator{
+		client:       c,
+		parent:       ctx,
+		ctx:          iterCtx,
+		cancel:       cancel,
+		topic:        topic,
+		subscription: subscription,
+		opts:         opts,
+	}
+}
+
+// Next advances to the next event, fetching the next page if needed. It returns false once
+// there are no more events, an error occurs or the iterator is closed.
+func (it *EventsIterator) Next() bool {
+	if it.done {
+		return false
+	}
+
+	if it.index >= len(it.page) && !it.fetch() {
+		it.current = nil
+		it.done = true
+		return false
+	}
+
+	it.current = it.page[it.index]
+	it.index++
+	return true
+}
+
+func (it *EventsIterator) fetch() bool {
+	if err := it.ctx.Err(); err != nil {
+		it.err = it.parent.Err()
+		return false
+	}
+
+	opts := it.opts
+	if it.fetched {
+		opts = append(opts[:len(opts):len(opts)], WithOffset(it.offset))
+	}
+
+	res, err := it.client.GetEvents(it.ctx, it.topic, it.subscription, opts...)
+	if err != nil {
+		// Closing the iterator cancels its context, which isn't an error for the caller
+		if it.ctx.Err() != nil && it.parent.Err() == nil {
+			return false
+		}
+		it.err = err
+		return false
+	}
+
+	it.fetched = true
+	it.page = res.Events
+	it.index = 0
+	it.offset = res.Offset + len(res.Events)
+
+	return len(it.page) > 0
+}
+
+// Event returns the current event, ready to be acknowledged.
+func (it *EventsIterator) Event() *Event {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *EventsIterator) Err() error {
+	return it.err
+}
+
+// Close stops the iterator, cancelling any in-flight fetch. It's safe to call more than once.
+func (it *EventsIterator) Close() {
+	it.cancel()
+}
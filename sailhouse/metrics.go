@@ -0,0 +1,29 @@
+package sailhouse
+
+import "time"
+
+// MetricsRecorder receives metrics from Subscribe, e.g. to back them with Prometheus.
+type MetricsRecorder interface {
+	// IncProcessed is called after the handler returns for an event.
+	IncProcessed(topic, subscription string)
+	// IncError is called when pulling events fails.
+	IncError(topic, subscription string)
+	// ObserveHandlerDuration is called with how long the handler took for an event.
+	ObserveHandlerDuration(topic, subscription string, d time.Duration)
+	// IncRetry is called when pulling events again after a failed pull.
+	IncRetry(topic, subscription string)
+	// IncAckError is called when acknowledging or nacking an event fails.
+	IncAckError(topic, subscription string)
+	// IncProcessedDropped is called when an event isn't sent on ProcessedEvents because the
+	// channel is full.
+	IncProcessedDropped(topic, subscription string)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) IncProcessed(string, string)                          {}
+func (noopMetrics) IncError(string, string)                              {}
+func (noopMetrics) ObserveHandlerDuration(string, string, time.Duration) {}
+func (noopMetrics) IncRetry(string, string)                              {}
+func (noopMetrics) IncAckError(string, string)                           {}
+func (noopMetrics) IncProcessedDropped(string, string)                   {}
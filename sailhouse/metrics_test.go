@@ -0,0 +1,107 @@
+package sailhouse
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingMetrics struct {
+	processed, errors, retries, ackErrors int32
+	durations, dropped                    int32
+}
+
+func (m *countingMetrics) IncProcessed(string, string) { atomic.AddInt32(&m.processed, 1) }
+func (m *countingMetrics) IncError(string, string)     { atomic.AddInt32(&m.errors, 1) }
+func (m *countingMetrics) IncRetry(string, string)     { atomic.AddInt32(&m.retries, 1) }
+func (m *countingMetrics) IncAckError(string, string)  { atomic.AddInt32(&m.ackErrors, 1) }
+func (m *countingMetrics) IncProcessedDropped(string, string) {
+	atomic.AddInt32(&m.dropped, 1)
+}
+func (m *countingMetrics) ObserveHandlerDuration(string, string, time.Duration) {
+	atomic.AddInt32(&m.durations, 1)
+}
+
+// waitFor polls cond until it's true or a second has passed.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSubscribeRecordsMetrics(t *testing.T) {
+	var pulls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		if atomic.AddInt32(&pulls, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"events":[{"id":"e1","data":{}}]}`))
+	})
+
+	metrics := &countingMetrics{}
+	sub := client.Subscribe(context.Background(), "topic", "sub", func(ctx context.Context, e *Event) {
+		e.Ack(ctx)
+	}, &SubscriptionOptions{
+		PollInterval: time.Millisecond,
+		ErrorBackoff: ConstantBackoff{Delay: time.Millisecond},
+		Metrics:      metrics,
+	})
+	waitFor(t, func() bool { return atomic.LoadInt32(&metrics.processed) > 0 })
+	sub.Stop()
+
+	if metrics.errors != 1 || metrics.retries != 1 {
+		t.Errorf("expected one pull error and one retry, got %d and %d", metrics.errors, metrics.retries)
+	}
+	if metrics.durations != metrics.processed {
+		t.Errorf("expected a duration per processed event, got %d for %d", metrics.durations, metrics.processed)
+	}
+}
+
+func TestSubscribeRecordsAckErrors(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"events":[{"id":"e1","data":{}}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	metrics := &countingMetrics{}
+	sub := client.Subscribe(context.Background(), "topic", "sub", func(context.Context, *Event) {}, &SubscriptionOptions{
+		PollInterval: time.Millisecond,
+		Idempotency:  failingStore{},
+		Metrics:      metrics,
+	})
+	waitFor(t, func() bool { return atomic.LoadInt32(&metrics.ackErrors) > 0 })
+	sub.Stop()
+}
+
+func TestSubscribeBatchRecordsAckErrors(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"events":[{"id":"e1","data":{}}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	metrics := &countingMetrics{}
+	sub := client.SubscribeBatch(context.Background(), "topic", "sub", 1, time.Hour, func(context.Context, []*Event) error {
+		return nil
+	}, &SubscriptionOptions{PollInterval: time.Millisecond, Metrics: metrics})
+	waitFor(t, func() bool { return atomic.LoadInt32(&metrics.ackErrors) > 0 })
+	sub.Stop()
+}
@@ -0,0 +1,85 @@
+package sailhouse
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// instruments are the OpenTelemetry metrics recorded by the client.
+type instruments struct {
+	published       metric.Int64Counter
+	consumed        metric.Int64Counter
+	acked           metric.Int64Counter
+	nacked          metric.Int64Counter
+	failed          metric.Int64Counter
+	publishDuration metric.Float64Histogram
+	handlerDuration metric.Float64Histogram
+}
+
+func newInstruments(mp metric.MeterProvider) *instruments {
+	if mp == nil {
+		mp = noop.NewMeterProvider()
+	}
+
+	meter := mp.Meter(instrumentationName)
+	fallback := noop.Meter{}
+	inst := &instruments{}
+
+	var err error
+	if inst.published, err = meter.Int64Counter("sailhouse.events.published", metric.WithDescription("Events published")); err != nil {
+		inst.published, _ = fallback.Int64Counter("")
+	}
+	if inst.consumed, err = meter.Int64Counter("sailhouse.events.consumed", metric.WithDescription("Events pulled from subscriptions")); err != nil {
+		inst.consumed, _ = fallback.Int64Counter("")
+	}
+	if inst.acked, err = meter.Int64Counter("sailhouse.events.acked", metric.WithDescription("Events acknowledged")); err != nil {
+		inst.acked, _ = fallback.Int64Counter("")
+	}
+	if inst.nacked, err = meter.Int64Counter("sailhouse.events.nacked", metric.WithDescription("Events negatively acknowledged")); err != nil {
+		inst.nacked, _ = fallback.Int64Counter("")
+	}
+	if inst.failed, err = meter.Int64Counter("sailhouse.events.failed", metric.WithDescription("Failed publishes, pulls, acks and nacks")); err != nil {
+		inst.failed, _ = fallback.Int64Counter("")
+	}
+	if inst.publishDuration, err = meter.Float64Histogram("sailhouse.publish.duration", metric.WithUnit("s"), metric.WithDescription("Publish latency")); err != nil {
+		inst.publishDuration, _ = fallback.Float64Histogram("")
+	}
+	if inst.handlerDuration, err = meter.Float64Histogram("sailhouse.handler.duration", metric.WithUnit("s"), metric.WithDescription("Subscribe handler latency")); err != nil {
+		inst.handlerDuration, _ = fallback.Float64Histogram("")
+	}
+
+	return inst
+}
+
+func metricAttrs(op, topic, subscription string) metric.MeasurementOption {
+	attrs := []attribute.KeyValue{
+		attribute.String("messaging.operation", op),
+		attribute.String("messaging.destination.name", topic),
+	}
+	if subscription != "" {
+		attrs = append(attrs, attribute.String("messaging.sailhouse.subscription", subscription))
+	}
+
+	return metric.WithAttributes(attrs...)
+}
+
+// recordResult counts a completed operation as a success on counter, or as a failure.
+func (i *instruments) recordResult(ctx context.Context, counter metric.Int64Counter, n int, err error, op, topic, subscription string) {
+	attrs := metricAttrs(op, topic, subscription)
+	if err != nil {
+		i.failed.Add(ctx, 1, attrs)
+		return
+	}
+
+	if n > 0 {
+		counter.Add(ctx, int64(n), attrs)
+	}
+}
+
+func (i *instruments) recordDuration(ctx context.Context, h metric.Float64Histogram, d time.Duration, op, topic, subscription string) {
+	h.Record(ctx, d.Seconds(), metricAttrs(op, topic, subscription))
+}
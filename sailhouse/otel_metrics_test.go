@@ -0,0 +1,147 @@
+package sailhouse
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// testMeter counts what's added to its counters and how many values its histograms record.
+type testMeter struct {
+	noop.Meter
+
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+func newTestMeter() *testMeter {
+	return &testMeter{counts: map[string]int64{}}
+}
+
+func (m *testMeter) add(name string, n int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.counts[name] += n
+}
+
+func (m *testMeter) count(name string) int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.counts[name]
+}
+
+func (m *testMeter) Int64Counter(name string, _ ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	return testCounter{meter: m, name: name}, nil
+}
+
+func (m *testMeter) Float64Histogram(name string, _ ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	return testHistogram{meter: m, name: name}, nil
+}
+
+type testCounter struct {
+	noop.Int64Counter
+	meter *testMeter
+	name  string
+}
+
+func (c testCounter) Add(_ context.Context, n int64, _ ...metric.AddOption) {
+	c.meter.add(c.name, n)
+}
+
+type testHistogram struct {
+	noop.Float64Histogram
+	meter *testMeter
+	name  string
+}
+
+func (h testHistogram) Record(context.Context, float64, ...metric.RecordOption) {
+	h.meter.add(h.name, 1)
+}
+
+type testMeterProvider struct {
+	noop.MeterProvider
+	meter *testMeter
+}
+
+func (p testMeterProvider) Meter(string, ...metric.MeterOption) metric.Meter {
+	return p.meter
+}
+
+func TestOTelMetricsCounters(t *testing.T) {
+	meter := newTestMeter()
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/topics/broken/events":
+			w.WriteHeader(http.StatusBadRequest)
+		case r.Method == http.MethodGet:
+			w.Write([]byte(`{"events":[{"id":"e1","data":{}},{"id":"e2","data":{}}]}`))
+		case r.URL.Path == "/topics/topic/events":
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}, func(o *SailhouseClientOptions) {
+		o.MeterProvider = testMeterProvider{meter: meter}
+	})
+	ctx := context.Background()
+
+	if err := client.Publish(ctx, "topic", "data"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Publish(ctx, "broken", "data"); err == nil {
+		t.Fatal("expected the publish to fail")
+	}
+	res, err := client.GetEvents(ctx, "topic", "sub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := res.Events[0].Ack(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := res.Events[1].Nack(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]int64{
+		"sailhouse.events.published": 1,
+		"sailhouse.events.failed":    1,
+		"sailhouse.events.consumed":  2,
+		"sailhouse.events.acked":     1,
+		"sailhouse.events.nacked":    1,
+		"sailhouse.publish.duration": 2,
+	}
+	for name, n := range want {
+		if got := meter.count(name); got != n {
+			t.Errorf("expected %s to be %d, got %d", name, n, got)
+		}
+	}
+}
+
+func TestOTelMetricsHandlerDuration(t *testing.T) {
+	meter := newTestMeter()
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"events":[{"id":"e1","data":{}}]}`))
+	}, func(o *SailhouseClientOptions) {
+		o.MeterProvider = testMeterProvider{meter: meter}
+	})
+
+	handled := make(chan struct{})
+	var once sync.Once
+	sub := client.Subscribe(context.Background(), "topic", "sub", func(ctx context.Context, e *Event) {
+		once.Do(func() { close(handled) })
+	}, &SubscriptionOptions{PollInterval: time.Millisecond})
+
+	<-handled
+	sub.Stop()
+
+	if meter.count("sailhouse.handler.duration") == 0 {
+		t.Error("expected the handler duration to be recorded")
+	}
+}
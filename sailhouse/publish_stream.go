@@ -0,0 +1,159 @@
+package sailhouse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+type PublishStreamOptions struct {
+	// Concurrency is the maximum number of publishes in flight at once. Defaults to 1.
+	Concurrency int
+	// PublishOptions are applied to every event published from the stream.
+	PublishOptions []publishOpt
+	// BatchSize publishes values that are already waiting on the input channel together with
+	// PublishBatch, up to this many at once. Every value is published on its own when zero or one.
+	BatchSize int
+}
+
+// PublishResult is the outcome of publishing a single event from PublishStream.
+type PublishResult struct {
+	Data any
+	Err  error
+}
+
+// PublishStream publishes every value received on in to the topic until in is closed or
+// the context is cancelled.
+//
+// A result is emitted for every value, in completion order, and the result channel is
+// closed once all publishes have finished.
+func (c *SailhouseClient) PublishStream(ctx context.Context, topic string, in <-chan any, opts *PublishStreamOptions) (<-chan PublishResult, error) {
+	ctx = orBackground(ctx)
+
+	if in == nil {
+		return nil, errors.New("input channel is nil")
+	}
+
+	concurrency := 1
+	batchSize := 1
+	var publishOpts []publishOpt
+	if opts != nil {
+		if opts.Concurrency > 0 {
+			concurrency = opts.Concurrency
+		}
+		if opts.BatchSize > 1 {
+			batchSize = opts.BatchSize
+		}
+		publishOpts = opts.PublishOptions
+	}
+
+	if c.stampCaller {
+		publishOpts = append(publishOpts[:len(publishOpts):len(publishOpts)], withCaller(captureCaller(1)))
+	}
+
+	results := make(chan PublishResult, concurrency)
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	go func() {
+		defer func() {
+			wg.Wait()
+			close(results)
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case data, ok := <-in:
+				if !ok {
+					return
+				}
+
+				batch, closed := collectBatch(in, data, batchSize)
+
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					for _, data := range batch {
+						results <- PublishResult{Data: data, Err: ctx.Err()}
+					}
+					return
+				}
+
+				wg.Add(1)
+				go func(batch []any) {
+					defer func() {
+						<-sem
+						wg.Done()
+					}()
+
+					for _, result := range c.publishStreamBatch(ctx, topic, batch, publishOpts) {
+						results <- result
+					}
+				}(batch)
+
+				if closed {
+					return
+				}
+			}
+		}
+	}()
+
+	return results, nil
+}
+
+// collectBatch adds values already waiting on in to first, up to size. It reports whether in
+// was closed while collecting.
+func collectBatch(in <-chan any, first any, size int) ([]any, bool) {
+	batch := []any{first}
+	for len(batch) < size {
+		select {
+		case data, ok := <-in:
+			if !ok {
+				return batch, true
+			}
+			batch = append(batch, data)
+		default:
+			return batch, false
+		}
+	}
+
+	return batch, false
+}
+
+// publishStreamBatch publishes the values, together when there's more than one, and returns
+// a result for each.
+func (c *SailhouseClient) publishStreamBatch(ctx context.Context, topic string, batch []any, opts []publishOpt) []PublishResult {
+	if len(batch) == 1 {
+		err := c.Publish(ctx, topic, batch[0], opts...)
+		return []PublishResult{{Data: batch[0], Err: err}}
+	}
+
+	events := make([]BatchEvent, len(batch))
+	for i, data := range batch {
+		events[i] = BatchEvent{Body: data}
+	}
+
+	_, err := c.PublishBatch(ctx, topic, events, opts...)
+
+	results := make([]PublishResult, len(batch))
+	for i, data := range batch {
+		results[i] = PublishResult{Data: data}
+	}
+
+	var batchErr *BatchPublishError
+	if errors.As(err, &batchErr) {
+		for _, failure := range batchErr.Failures {
+			results[failure.Index].Err = opError("publish", topic, "", fmt.Errorf("event %d of batch: %s", failure.Index, failure.Message))
+		}
+		return results
+	}
+
+	for i := range results {
+		results[i].Err = err
+	}
+
+	return results
+}
@@ -0,0 +1,119 @@
+package sailhouse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func collectResults(t *testing.T, results <-chan PublishResult) []PublishResult {
+	t.Helper()
+
+	var all []PublishResult
+	for r := range results {
+		all = append(all, r)
+	}
+	return all
+}
+
+func TestPublishStreamPublishesEveryValue(t *testing.T) {
+	var publishes int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&publishes, 1)
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	in := make(chan any)
+	results, err := client.PublishStream(context.Background(), "topic", in, &PublishStreamOptions{Concurrency: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		for i := 0; i < 5; i++ {
+			in <- i
+		}
+		close(in)
+	}()
+
+	all := collectResults(t, results)
+	if len(all) != 5 {
+		t.Fatalf("expected 5 results, got %d", len(all))
+	}
+	for _, r := range all {
+		if r.Err != nil {
+			t.Errorf("value %v: unexpected error %v", r.Data, r.Err)
+		}
+	}
+	if publishes != 5 {
+		t.Errorf("expected 5 publishes, got %d", publishes)
+	}
+}
+
+func TestPublishStreamBatchesWaitingValues(t *testing.T) {
+	var batches, single int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/batch") {
+			atomic.AddInt32(&single, 1)
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+
+		atomic.AddInt32(&batches, 1)
+		var body []map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+
+		type result struct {
+			ID    string `json:"id,omitempty"`
+			Error string `json:"error,omitempty"`
+		}
+		res := struct {
+			Results []result `json:"results"`
+		}{}
+		for _, e := range body {
+			if e["data"] == "bad" {
+				res.Results = append(res.Results, result{Error: "rejected"})
+				continue
+			}
+			res.Results = append(res.Results, result{ID: "id"})
+		}
+
+		w.WriteHeader(http.StatusMultiStatus)
+		json.NewEncoder(w).Encode(res)
+	})
+
+	in := make(chan any, 4)
+	in <- "a"
+	in <- "bad"
+	in <- "c"
+	in <- "d"
+	close(in)
+
+	results, err := client.PublishStream(context.Background(), "topic", in, &PublishStreamOptions{BatchSize: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	all := collectResults(t, results)
+	if len(all) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(all))
+	}
+	for _, r := range all {
+		if (r.Data == "bad") != (r.Err != nil) {
+			t.Errorf("value %v: unexpected result error %v", r.Data, r.Err)
+		}
+	}
+	if batches != 1 || single != 0 {
+		t.Errorf("expected a single batch request, got %d batches and %d single publishes", batches, single)
+	}
+}
+
+func TestPublishStreamNilInput(t *testing.T) {
+	client := NewSailhouseClient("token")
+	if _, err := client.PublishStream(context.Background(), "topic", nil, nil); err == nil {
+		t.Fatal("expected an error for a nil input channel")
+	}
+}
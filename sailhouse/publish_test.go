@@ -0,0 +1,273 @@
+package sailhouse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newPublishClient returns a client whose publishes are decoded onto bodies.
+func newPublishClient(t *testing.T, opts ...func(*SailhouseClientOptions)) (*SailhouseClient, <-chan map[string]any) {
+	t.Helper()
+
+	bodies := make(chan map[string]any, 1)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies <- body
+		w.WriteHeader(http.StatusCreated)
+	}, opts...)
+
+	return client, bodies
+}
+
+func TestPublishScheduledTime(t *testing.T) {
+	tests := []struct {
+		name    string
+		sendAt  time.Time
+		opts    []publishOpt
+		wantErr bool
+	}{
+		{name: "past", sendAt: time.Now().Add(-time.Hour), wantErr: true},
+		{name: "near now", sendAt: time.Now().Add(-time.Second)},
+		{name: "future", sendAt: time.Now().Add(time.Hour)},
+		{name: "past allowed", sendAt: time.Now().Add(-time.Hour), opts: []publishOpt{AllowPastSchedule()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, bodies := newPublishClient(t)
+
+			opts := append([]publishOpt{WithScheduledTime(tt.sendAt)}, tt.opts...)
+			err := client.Publish(context.Background(), "topic", "data", opts...)
+
+			if tt.wantErr {
+				if !errors.Is(err, ErrScheduledInPast) {
+					t.Fatalf("expected ErrScheduledInPast, got %v", err)
+				}
+				select {
+				case <-bodies:
+					t.Error("expected no request for a rejected schedule")
+				default:
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			body := <-bodies
+			if body[FieldSendAt] != tt.sendAt.Format(time.RFC3339) {
+				t.Errorf("expected send_at %s, got %v", tt.sendAt.Format(time.RFC3339), body[FieldSendAt])
+			}
+		})
+	}
+}
+
+func TestHeadersRoundTrip(t *testing.T) {
+	stored := make(chan json.RawMessage, 1)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			var body struct {
+				Headers  json.RawMessage `json:"headers"`
+				Metadata json.RawMessage `json:"metadata"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body.Metadata != nil {
+				t.Errorf("expected headers to stay out of the metadata, got %s", body.Metadata)
+			}
+			stored <- body.Headers
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+
+		headers := <-stored
+		w.Write([]byte(`{"events":[{"id":"e1","data":{},"headers":` + string(headers) + `}]}`))
+	})
+
+	headers := map[string]string{"content-type": "application/json", "routing-key": "eu"}
+	if err := client.Publish(context.Background(), "topic", "data", WithHeaders(headers)); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := client.GetEvents(context.Background(), "topic", "sub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Events) != 1 {
+		t.Fatalf("expected one event, got %d", len(res.Events))
+	}
+
+	got := res.Events[0].Headers
+	if len(got) != len(headers) || got["content-type"] != "application/json" || got["routing-key"] != "eu" {
+		t.Errorf("expected the published headers, got %v", got)
+	}
+}
+
+func TestPublishStampsCaller(t *testing.T) {
+	client, bodies := newPublishClient(t, func(o *SailhouseClientOptions) { o.StampCaller = true })
+
+	_, _, line, _ := runtime.Caller(0)
+	err := client.Publish(context.Background(), "topic", "data", WithMetaData(map[string]any{"tenant": "acme"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	metadata, _ := (<-bodies)["metadata"].(map[string]any)
+	if metadata["tenant"] != "acme" {
+		t.Errorf("expected the existing metadata to be kept, got %v", metadata)
+	}
+
+	caller, ok := metadata[CallerMetadataKey].(map[string]any)
+	if !ok {
+		t.Fatalf("expected caller metadata, got %v", metadata)
+	}
+	if fn, _ := caller["function"].(string); !strings.HasSuffix(fn, "TestPublishStampsCaller") {
+		t.Errorf("expected the publishing function, got %q", fn)
+	}
+	if file, _ := caller["file"].(string); !strings.HasSuffix(file, "publish_test.go") {
+		t.Errorf("expected the publishing file, got %q", file)
+	}
+	if caller["line"] != float64(line+1) {
+		t.Errorf("expected line %d, got %v", line+1, caller["line"])
+	}
+}
+
+func TestPublishWithoutStampCaller(t *testing.T) {
+	client, bodies := newPublishClient(t)
+
+	if err := client.Publish(context.Background(), "topic", "data"); err != nil {
+		t.Fatal(err)
+	}
+
+	if metadata, ok := (<-bodies)["metadata"]; ok {
+		t.Errorf("expected no metadata, got %v", metadata)
+	}
+}
+
+func TestPublishWithMetaDataStruct(t *testing.T) {
+	type orderMeta struct {
+		Tenant   string `json:"tenant"`
+		Priority int    `json:"priority"`
+		Region   string `json:"region,omitempty"`
+		internal string
+	}
+
+	client, bodies := newPublishClient(t)
+
+	meta := orderMeta{Tenant: "acme", Priority: 2, internal: "hidden"}
+	if err := client.Publish(context.Background(), "topic", "data", WithMetaDataStruct(meta)); err != nil {
+		t.Fatal(err)
+	}
+
+	metadata, _ := (<-bodies)["metadata"].(map[string]any)
+	want := map[string]any{"tenant": "acme", "priority": float64(2)}
+	if len(metadata) != len(want) || metadata["tenant"] != want["tenant"] || metadata["priority"] != want["priority"] {
+		t.Errorf("expected %v, got %v", want, metadata)
+	}
+}
+
+func TestPublishWithMetaDataStructRejectsNonObjects(t *testing.T) {
+	client, bodies := newPublishClient(t)
+
+	if err := client.Publish(context.Background(), "topic", "data", WithMetaDataStruct("not an object")); err == nil {
+		t.Fatal("expected an error for metadata that isn't an object")
+	}
+
+	select {
+	case body := <-bodies:
+		t.Errorf("expected nothing to be published, got %v", body)
+	default:
+	}
+}
+
+func TestPublishWithFieldNames(t *testing.T) {
+	client, bodies := newPublishClient(t, func(o *SailhouseClientOptions) {
+		o.FieldNames = map[string]string{
+			FieldData:     "payload",
+			FieldMetadata: "meta",
+			FieldSendAt:   "deliver_at",
+		}
+	})
+
+	sendAt := time.Now().Add(time.Hour)
+	err := client.Publish(context.Background(), "topic", map[string]any{"a": "b"},
+		WithMetaData(map[string]any{"tenant": "acme"}),
+		WithScheduledTime(sendAt),
+		WithEventID("e1"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := <-bodies
+	for _, renamed := range []string{FieldData, FieldMetadata, FieldSendAt} {
+		if _, ok := body[renamed]; ok {
+			t.Errorf("expected %q to be renamed, got %v", renamed, body)
+		}
+	}
+	if payload, _ := body["payload"].(map[string]any); payload["a"] != "b" {
+		t.Errorf("expected the data under payload, got %v", body)
+	}
+	if meta, _ := body["meta"].(map[string]any); meta["tenant"] != "acme" {
+		t.Errorf("expected the metadata under meta, got %v", body)
+	}
+	if _, ok := body["deliver_at"].(string); !ok {
+		t.Errorf("expected the schedule under deliver_at, got %v", body)
+	}
+	if body[FieldID] != "e1" {
+		t.Errorf("expected fields without an override to keep their name, got %v", body)
+	}
+}
+
+func TestPublishWithEventID(t *testing.T) {
+	var mu sync.Mutex
+	published := map[string]bool{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			ID string `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if published[body.ID] {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"message":"event already exists"}`))
+			return
+		}
+		published[body.ID] = true
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	if err := client.Publish(context.Background(), "topic", "data", WithEventID("order-1")); err != nil {
+		t.Fatal(err)
+	}
+
+	err := client.Publish(context.Background(), "topic", "data", WithEventID("order-1"))
+	if !errors.Is(err, ErrDuplicateEvent) {
+		t.Errorf("expected ErrDuplicateEvent for a reused ID, got %v", err)
+	}
+}
+
+func TestPublishBatchRejectsEventID(t *testing.T) {
+	client, bodies := newPublishClient(t)
+
+	events := []BatchEvent{{Body: "a"}, {Body: "b"}}
+	_, err := client.PublishBatch(context.Background(), "topic", events, WithEventID("order-1"))
+	if !errors.Is(err, ErrEventIDInBatch) {
+		t.Fatalf("expected ErrEventIDInBatch, got %v", err)
+	}
+
+	select {
+	case <-bodies:
+		t.Error("expected nothing to be published")
+	default:
+	}
+}
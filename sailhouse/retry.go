@@ -0,0 +1,142 @@
+package sailhouse
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how the client retries failed requests.
+//
+// GET requests are retried automatically. POST and PUT requests are only retried
+// when RetryNonIdempotent is set or they carry an idempotency key, as retrying them
+// may repeat their side effects.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, growing exponentially after that.
+	// Defaults to 200ms.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Backoff computes the delay between attempts instead of BaseDelay and MaxDelay. It's
+	// shared by concurrent requests, so it shouldn't keep state between calls.
+	Backoff BackoffStrategy
+	// RetryableStatusCodes are the response status codes that are retried.
+	// Defaults to 429, 500, 502, 503 and 504.
+	RetryableStatusCodes []int
+	// RetryNonIdempotent allows POST and PUT requests to be retried.
+	RetryNonIdempotent bool
+}
+
+var defaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+const defaultRetryBaseDelay = 200 * time.Millisecond
+
+// RetryError is returned when a request still fails after being retried, whether with a
+// network error or a retryable status. In the latter case Err is the *SailhouseAPIError.
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("request failed after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+func (p *RetryPolicy) attemptsFor(req *http.Request) int {
+	if p == nil || p.MaxAttempts <= 1 {
+		return 1
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		return p.MaxAttempts
+	}
+
+	replayable := req.Body == nil || req.GetBody != nil
+	if replayable && (p.RetryNonIdempotent || req.Header.Get(idempotencyKeyHeader) != "") {
+		return p.MaxAttempts
+	}
+
+	return 1
+}
+
+func (p *RetryPolicy) retryableStatus(code int) bool {
+	if p == nil {
+		return false
+	}
+
+	codes := p.RetryableStatusCodes
+	if len(codes) == 0 {
+		codes = defaultRetryableStatusCodes
+	}
+
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *RetryPolicy) backoff() BackoffStrategy {
+	if p.Backoff != nil {
+		return p.Backoff
+	}
+
+	base := p.BaseDelay
+	if base <= 0 {
+		base = defaultRetryBaseDelay
+	}
+
+	return ExponentialBackoff{
+		Base:   base,
+		Max:    p.MaxDelay,
+		Factor: 2,
+		Jitter: 0.5,
+	}
+}
+
+// retriedBody marks the body of a response that still had a retryable status after the last
+// attempt, so the error built from it reports how many attempts were made.
+type retriedBody struct {
+	io.ReadCloser
+	attempts int
+}
+
+// retryAfter parses a Retry-After header, given in seconds or as an HTTP date.
+func retryAfter(res *http.Response) (time.Duration, bool) {
+	header := res.Header.Get("Retry-After")
+	if header == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(header); err == nil {
+		return time.Until(at), true
+	}
+
+	return 0, false
+}
+
+// discard drains and closes a response body so the connection can be reused.
+func discard(res *http.Response) {
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+}
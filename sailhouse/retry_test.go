@@ -0,0 +1,144 @@
+package sailhouse
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryExhaustedStatusReturnsRetryError(t *testing.T) {
+	var hits int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}, func(o *SailhouseClientOptions) {
+		o.RetryPolicy = &RetryPolicy{
+			MaxAttempts:          3,
+			BaseDelay:            time.Millisecond,
+			RetryableStatusCodes: []int{http.StatusNotFound},
+		}
+	})
+
+	_, err := client.GetEvents(context.Background(), "topic", "sub")
+
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("expected a RetryError, got %v", err)
+	}
+	if retryErr.Attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", retryErr.Attempts)
+	}
+	if hits != 3 {
+		t.Errorf("expected 3 requests, got %d", hits)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected the error to match ErrNotFound, got %v", err)
+	}
+}
+
+func TestRetryRecoversAfterRetryableStatus(t *testing.T) {
+	var hits int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"events":[]}`))
+	}, func(o *SailhouseClientOptions) {
+		o.RetryPolicy = &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	})
+
+	if _, err := client.GetEvents(context.Background(), "topic", "sub"); err != nil {
+		t.Fatalf("expected the third attempt to succeed, got %v", err)
+	}
+}
+
+func TestRetryPostNotRetriedWithoutIdempotency(t *testing.T) {
+	var hits int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}, func(o *SailhouseClientOptions) {
+		o.RetryPolicy = &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	})
+
+	err := client.Publish(context.Background(), "topic", map[string]any{"a": 1})
+
+	var retryErr *RetryError
+	if errors.As(err, &retryErr) {
+		t.Errorf("expected no RetryError for a single attempt, got %v", err)
+	}
+	if hits != 1 {
+		t.Errorf("expected 1 request, got %d", hits)
+	}
+}
+
+func TestRetryBaseDelayDefault(t *testing.T) {
+	p := &RetryPolicy{MaxAttempts: 3}
+
+	backoff, ok := p.backoff().(ExponentialBackoff)
+	if !ok {
+		t.Fatalf("expected an ExponentialBackoff, got %T", p.backoff())
+	}
+	if backoff.Base != defaultRetryBaseDelay {
+		t.Errorf("expected base delay %s, got %s", defaultRetryBaseDelay, backoff.Base)
+	}
+}
+
+func TestRetryKeepsIdempotencyKey(t *testing.T) {
+	var hits int32
+	keys := make(chan string, 2)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		keys <- r.Header.Get("Idempotency-Key")
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}, func(o *SailhouseClientOptions) {
+		o.RetryPolicy = &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	})
+
+	err := client.Publish(context.Background(), "topic", map[string]any{"a": 1}, WithIdempotencyKey("order-1"))
+	if err != nil {
+		t.Fatalf("expected the retry to succeed, got %v", err)
+	}
+	close(keys)
+
+	var got []string
+	for key := range keys {
+		got = append(got, key)
+	}
+	if len(got) != 2 || got[0] != "order-1" || got[1] != "order-1" {
+		t.Errorf("expected the same key on both attempts, got %v", got)
+	}
+}
+
+func TestRetryUsesCustomBackoff(t *testing.T) {
+	// BaseDelay would stall the test if the custom backoff weren't used
+	backoff := &recordingBackoff{}
+	var hits int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"events":[]}`))
+	}, func(o *SailhouseClientOptions) {
+		o.RetryPolicy = &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour, Backoff: backoff}
+	})
+
+	if _, err := client.GetEvents(context.Background(), "topic", "sub"); err != nil {
+		t.Fatalf("expected the third attempt to succeed, got %v", err)
+	}
+
+	backoff.mu.Lock()
+	defer backoff.mu.Unlock()
+	if !reflect.DeepEqual(backoff.attempts, []int{0, 1}) {
+		t.Errorf("expected the custom backoff before each retry, got %v", backoff.attempts)
+	}
+}
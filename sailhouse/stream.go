@@ -0,0 +1,377 @@
+package sailhouse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+type StreamOptions struct {
+	// MaxMessageBytes limits the size of a single message read from the stream.
+	// Gorilla's default limit is used when zero.
+	MaxMessageBytes int64
+	// ConnectTimeout bounds each dial, including reconnects, without putting a deadline on the
+	// stream itself. Dials are only bounded by the context when zero.
+	ConnectTimeout time.Duration
+	// Reconnect re-establishes the connection when it drops instead of ending the stream.
+	Reconnect bool
+	// MaxReconnects limits the number of consecutive reconnect attempts. Unlimited when zero.
+	MaxReconnects int
+	// Backoff is the delay between reconnect attempts. Defaults to 1s growing to 30s.
+	Backoff BackoffStrategy
+	// Reconnected receives a notification whenever the stream reconnects. Sends block until
+	// received or the context is cancelled, so the channel should be read or buffered.
+	Reconnected chan<- StreamReconnect
+}
+
+// StreamReconnect describes a successful reconnect of an event stream.
+type StreamReconnect struct {
+	// Attempts is the number of attempts it took to reconnect.
+	Attempts int
+	// Err is the error that dropped the previous connection.
+	Err error
+}
+
+// ErrMessageTooLarge is sent on the error channel when a streamed message exceeds MaxMessageBytes.
+// The stream isn't reconnected, as the server would send the same message again.
+var ErrMessageTooLarge = errors.New("stream message too large")
+
+// ErrStreamUnauthorized is sent on the error channel when the server rejects the stream's token.
+// The stream isn't reconnected, as retrying with the same token would fail again.
+var ErrStreamUnauthorized = errors.New("stream unauthorized")
+
+// Close codes the server uses when it rejects the auth message.
+const (
+	closeUnauthorized = 4001
+	closeForbidden    = 4003
+)
+
+var defaultStreamBackoff = ExponentialBackoff{
+	Base:   time.Second,
+	Max:    30 * time.Second,
+	Factor: 2,
+	Jitter: 0.2,
+}
+
+// maxTrackedAcks bounds how many acknowledged event IDs a stream remembers across reconnects.
+const maxTrackedAcks = 1024
+
+// eventStream is the state shared by a stream and the events it delivers.
+type eventStream struct {
+	client       *SailhouseClient
+	topic        string
+	subscription string
+	opts         StreamOptions
+	done         <-chan struct{}
+	events       chan Event
+	errs         chan error
+
+	mu    sync.Mutex
+	acked map[string]struct{}
+	order []string
+}
+
+// markAcked records an event acknowledged by the consumer, so it isn't delivered again if the
+// server resends it after a reconnect.
+func (s *eventStream) markAcked(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.acked[id]; ok {
+		return
+	}
+
+	if len(s.order) >= maxTrackedAcks {
+		delete(s.acked, s.order[0])
+		s.order = s.order[1:]
+	}
+
+	s.acked[id] = struct{}{}
+	s.order = append(s.order, id)
+}
+
+func (s *eventStream) isAcked(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.acked[id]
+	return ok
+}
+
+// streamConn is a stream connection shared with the events read from it, so they can write
+// acks back on it.
+type streamConn struct {
+	conn *websocket.Conn
+	mu   sync.Mutex
+}
+
+func (c *streamConn) writeJSON(ctx context.Context, v any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	deadline, _ := ctx.Deadline()
+	if err := c.conn.SetWriteDeadline(deadline); err != nil {
+		return err
+	}
+
+	return c.conn.WriteJSON(v)
+}
+
+// streamURL derives the websocket URL from the client's base URL.
+func (c *SailhouseClient) streamURL() (string, error) {
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL: %w", err)
+	}
+
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	case "http":
+		u.Scheme = "ws"
+	}
+
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/events/stream"
+
+	return u.String(), nil
+}
+
+func (c *SailhouseClient) StreamEvents(ctx context.Context, topic string, subscription string) (<-chan Event, <-chan error) {
+	return c.StreamEventsWithOptions(ctx, topic, subscription, StreamOptions{})
+}
+
+// StreamEventsWithOptions streams events over a websocket until the context is cancelled or the
+// connection fails. Both channels are closed when the stream ends.
+//
+// Errors that don't end the stream, such as a malformed message, are also sent on the error
+// channel. Events stop flowing until each error is received, so the error channel must be read
+// alongside the event channel.
+//
+// With Reconnect set, a dropped connection is re-established and re-authenticated rather than
+// ending the stream. Events acknowledged before the drop aren't delivered again.
+func (c *SailhouseClient) StreamEventsWithOptions(ctx context.Context, topic string, subscription string, opts StreamOptions) (<-chan Event, <-chan error) {
+	ctx = orBackground(ctx)
+
+	s := &eventStream{
+		client:       c,
+		topic:        topic,
+		subscription: subscription,
+		opts:         opts,
+		done:         ctx.Done(),
+		events:       make(chan Event),
+		errs:         make(chan error, 1),
+		acked:        map[string]struct{}{},
+	}
+
+	conn, err := s.dial(ctx)
+	if err != nil {
+		s.errs <- opError("stream", topic, subscription, err)
+		close(s.errs)
+		close(s.events)
+		return s.events, s.errs
+	}
+
+	go func() {
+		defer func() {
+			close(s.events)
+			close(s.errs)
+		}()
+
+		for {
+			err := s.read(conn)
+			if err == nil {
+				return
+			}
+
+			conn, err = s.reconnect(ctx, err)
+			if err != nil {
+				s.sendErr(err)
+				return
+			}
+			if conn == nil {
+				// Cancelled while reconnecting
+				return
+			}
+		}
+	}()
+
+	return s.events, s.errs
+}
+
+// dial connects to the websocket and sends the auth message.
+func (s *eventStream) dial(ctx context.Context) (*websocket.Conn, error) {
+	u, err := s.client.streamURL()
+	if err != nil {
+		return nil, err
+	}
+
+	dialCtx := ctx
+	if s.opts.ConnectTimeout > 0 {
+		var cancel context.CancelFunc
+		dialCtx, cancel = context.WithTimeout(ctx, s.opts.ConnectTimeout)
+		defer cancel()
+	}
+
+	conn, res, err := s.client.dialer.DialContext(dialCtx, u, nil)
+	if err != nil {
+		if res != nil && (res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden) {
+			return nil, fmt.Errorf("%w: handshake returned %d", ErrStreamUnauthorized, res.StatusCode)
+		}
+		return nil, fmt.Errorf("failed to connect to websocket: %w", err)
+	}
+
+	if s.opts.MaxMessageBytes > 0 {
+		conn.SetReadLimit(s.opts.MaxMessageBytes)
+	}
+
+	err = conn.WriteJSON(map[string]interface{}{
+		"topic_slug":        s.topic,
+		"subscription_slug": s.subscription,
+		"token":             s.client.token,
+	})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to send auth message: %w", err)
+	}
+
+	return conn, nil
+}
+
+// reconnect re-establishes a dropped connection with backoff. It returns the error that ended
+// the stream if reconnecting is disabled or exhausted, or a nil connection and error if the
+// context is cancelled.
+func (s *eventStream) reconnect(ctx context.Context, cause error) (*websocket.Conn, error) {
+	if !s.opts.Reconnect || errors.Is(cause, ErrStreamUnauthorized) || errors.Is(cause, ErrMessageTooLarge) {
+		return nil, cause
+	}
+
+	var backoff BackoffStrategy = defaultStreamBackoff
+	if s.opts.Backoff != nil {
+		backoff = s.opts.Backoff
+	}
+	defer backoff.Reset()
+
+	err := cause
+	for attempt := 1; s.opts.MaxReconnects <= 0 || attempt <= s.opts.MaxReconnects; attempt++ {
+		select {
+		case <-time.After(backoff.NextDelay(attempt - 1)):
+		case <-s.done:
+			return nil, nil
+		}
+
+		var conn *websocket.Conn
+		conn, err = s.dial(ctx)
+		if errors.Is(err, ErrStreamUnauthorized) {
+			return nil, err
+		}
+		if err != nil {
+			continue
+		}
+
+		if s.opts.Reconnected != nil {
+			select {
+			case s.opts.Reconnected <- StreamReconnect{Attempts: attempt, Err: cause}:
+			case <-s.done:
+				conn.Close()
+				return nil, nil
+			}
+		}
+
+		return conn, nil
+	}
+
+	return nil, fmt.Errorf("failed to reconnect after %d attempts: %w", s.opts.MaxReconnects, err)
+}
+
+func (s *eventStream) sendErr(err error) bool {
+	select {
+	case s.errs <- opError("stream", s.topic, s.subscription, err):
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+// read delivers events from a single connection until it fails or the context is cancelled,
+// in which case nil is returned. The connection is closed before returning.
+func (s *eventStream) read(conn *websocket.Conn) error {
+	messages := make(chan []byte)
+	readErrs := make(chan error, 1)
+	stop := make(chan struct{})
+	shared := &streamConn{conn: conn}
+
+	defer func() {
+		close(stop)
+		conn.Close()
+	}()
+
+	// The reader only hands messages over; the loop below owns the output channels
+	go func() {
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				readErrs <- err
+				return
+			}
+
+			select {
+			case messages <- message:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-s.done:
+			return nil
+		case err := <-readErrs:
+			if errors.Is(err, websocket.ErrReadLimit) {
+				return fmt.Errorf("%w: limit is %d bytes", ErrMessageTooLarge, s.opts.MaxMessageBytes)
+			}
+			if websocket.IsCloseError(err, closeUnauthorized, closeForbidden, websocket.ClosePolicyViolation) {
+				return fmt.Errorf("%w: %v", ErrStreamUnauthorized, err)
+			}
+			return fmt.Errorf("failed to read message: %w", err)
+		case message := <-messages:
+			var event Event
+			err := json.Unmarshal(message, &event)
+			if err != nil {
+				// A malformed message shouldn't end the stream, so report it and carry on
+				if !s.sendErr(fmt.Errorf("failed to unmarshal message: %w", err)) {
+					return nil
+				}
+				continue
+			}
+
+			if s.isAcked(event.ID) {
+				continue
+			}
+
+			event.topic = s.topic
+			event.subscription = s.subscription
+			event.client = s.client
+			event.stream = s
+			event.conn = shared
+
+			select {
+			case s.events <- event:
+			case <-s.done:
+				return nil
+			}
+		}
+	}
+}
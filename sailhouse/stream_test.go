@@ -0,0 +1,434 @@
+package sailhouse
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// newStreamClient starts a websocket server that reads the auth message, then hands the
+// connection to serve. The connection is closed once serve returns.
+func newStreamClient(t *testing.T, serve func(conn *websocket.Conn)) *SailhouseClient {
+	t.Helper()
+
+	upgrader := websocket.Upgrader{}
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+		serve(conn)
+	})
+}
+
+func TestStreamEventsSkipsMalformedMessages(t *testing.T) {
+	client := newStreamClient(t, func(conn *websocket.Conn) {
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"id":"e1","data":{"a":`))
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"id":"e2","data":{"a":1}}`))
+		conn.ReadMessage()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	events, errs := client.StreamEvents(ctx, "topic", "sub")
+
+	if err := <-errs; err == nil {
+		t.Fatal("expected an error for the malformed message")
+	}
+
+	select {
+	case event := <-events:
+		if event.ID != "e2" {
+			t.Errorf("expected the stream to carry on with e2, got %s", event.ID)
+		}
+	case <-ctx.Done():
+		t.Fatal("expected the stream to continue after a malformed message")
+	}
+}
+
+func TestStreamEventsRejectsOversizedMessages(t *testing.T) {
+	client := newStreamClient(t, func(conn *websocket.Conn) {
+		payload := `{"id":"e1","data":{"a":"` + strings.Repeat("x", 2048) + `"}}`
+		conn.WriteMessage(websocket.TextMessage, []byte(payload))
+		conn.ReadMessage()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	events, errs := client.StreamEventsWithOptions(ctx, "topic", "sub", StreamOptions{MaxMessageBytes: 1024})
+
+	err := <-errs
+	if !errors.Is(err, ErrMessageTooLarge) {
+		t.Fatalf("expected ErrMessageTooLarge, got %v", err)
+	}
+	if _, ok := <-events; ok {
+		t.Error("expected no events from the oversized message")
+	}
+}
+
+func TestStreamEventsConnectTimeout(t *testing.T) {
+	// Accepts connections but never answers the handshake
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	var conns []net.Conn
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			conns = append(conns, conn)
+			mu.Unlock()
+		}
+	}()
+	defer func() {
+		ln.Close()
+		mu.Lock()
+		defer mu.Unlock()
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+
+	client := NewSailhouseClientWithOptions(SailhouseClientOptions{Token: "token", BaseURL: "http://" + ln.Addr().String()})
+
+	start := time.Now()
+	_, errs := client.StreamEventsWithOptions(context.Background(), "topic", "sub", StreamOptions{ConnectTimeout: 50 * time.Millisecond})
+
+	err = <-errs
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Errorf("expected the dial to time out, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the dial to give up at the connect timeout, took %v", elapsed)
+	}
+}
+
+func TestStreamEventsUnauthorizedCloseDoesNotReconnect(t *testing.T) {
+	var connections int32
+	client := newStreamClient(t, func(conn *websocket.Conn) {
+		atomic.AddInt32(&connections, 1)
+		msg := websocket.FormatCloseMessage(closeUnauthorized, "invalid token")
+		conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	events, errs := client.StreamEventsWithOptions(ctx, "topic", "sub", StreamOptions{
+		Reconnect: true,
+		Backoff:   ConstantBackoff{Delay: time.Millisecond},
+	})
+
+	err := <-errs
+	if !errors.Is(err, ErrStreamUnauthorized) {
+		t.Fatalf("expected ErrStreamUnauthorized, got %v", err)
+	}
+	if _, ok := <-events; ok {
+		t.Error("expected the stream to end")
+	}
+	if n := atomic.LoadInt32(&connections); n != 1 {
+		t.Errorf("expected no reconnects, got %d connections", n)
+	}
+}
+
+func TestStreamEventsUnauthorizedHandshake(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	_, errs := client.StreamEventsWithOptions(context.Background(), "topic", "sub", StreamOptions{Reconnect: true})
+
+	if err := <-errs; !errors.Is(err, ErrStreamUnauthorized) {
+		t.Errorf("expected ErrStreamUnauthorized, got %v", err)
+	}
+}
+
+func TestAckOverStreamWritesAckFrames(t *testing.T) {
+	frames := make(chan map[string]string, 2)
+	client := newStreamClient(t, func(conn *websocket.Conn) {
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"id":"e1","data":{}}`))
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"id":"e2","data":{}}`))
+		for i := 0; i < 2; i++ {
+			var frame map[string]string
+			if err := conn.ReadJSON(&frame); err != nil {
+				return
+			}
+			frames <- frame
+		}
+		conn.ReadMessage()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	events, _ := client.StreamEvents(ctx, "topic", "sub")
+
+	// Acks are written concurrently, so they rely on the connection's write lock
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		event := <-events
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := event.AckOverStream(ctx); err != nil {
+				t.Errorf("ack %s: %v", event.ID, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	acked := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		select {
+		case frame := <-frames:
+			if frame["type"] != "ack" {
+				t.Errorf("expected an ack frame, got %v", frame)
+			}
+			acked[frame["event_id"]] = true
+		case <-ctx.Done():
+			t.Fatal("expected two ack frames")
+		}
+	}
+	if !acked["e1"] || !acked["e2"] {
+		t.Errorf("expected acks for e1 and e2, got %v", acked)
+	}
+}
+
+func TestAckOverStreamRequiresStreamedEvent(t *testing.T) {
+	event := &Event{ID: "e1"}
+
+	if err := event.AckOverStream(context.Background()); !errors.Is(err, ErrNotStreamed) {
+		t.Errorf("expected ErrNotStreamed, got %v", err)
+	}
+}
+
+// reconnectServer serves each websocket connection with the serve function for its number,
+// starting at 1, and accepts acks sent over HTTP.
+func reconnectServer(t *testing.T, serve func(n int32, conn *websocket.Conn)) *SailhouseClient {
+	t.Helper()
+
+	var connections int32
+	upgrader := websocket.Upgrader{}
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+		serve(atomic.AddInt32(&connections, 1), conn)
+	})
+}
+
+func TestStreamEventsReconnectSkipsAckedEvents(t *testing.T) {
+	drop := make(chan struct{})
+	client := reconnectServer(t, func(n int32, conn *websocket.Conn) {
+		if n == 1 {
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"id":"e1","data":{}}`))
+			<-drop
+			return
+		}
+
+		// The server resends e1 after the reconnect
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"id":"e1","data":{}}`))
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"id":"e2","data":{}}`))
+		conn.ReadMessage()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	reconnected := make(chan StreamReconnect, 1)
+	events, _ := client.StreamEventsWithOptions(ctx, "topic", "sub", StreamOptions{
+		Reconnect:   true,
+		Backoff:     ConstantBackoff{Delay: time.Millisecond},
+		Reconnected: reconnected,
+	})
+
+	first := <-events
+	if first.ID != "e1" {
+		t.Fatalf("expected e1, got %s", first.ID)
+	}
+	if err := first.Ack(ctx); err != nil {
+		t.Fatal(err)
+	}
+	close(drop)
+
+	select {
+	case r := <-reconnected:
+		if r.Attempts != 1 || r.Err == nil {
+			t.Errorf("expected one attempt after a dropped connection, got %+v", r)
+		}
+	case <-ctx.Done():
+		t.Fatal("expected the stream to reconnect")
+	}
+
+	select {
+	case event := <-events:
+		if event.ID != "e2" {
+			t.Errorf("expected the acked e1 to be skipped, got %s", event.ID)
+		}
+	case <-ctx.Done():
+		t.Fatal("expected e2 after reconnecting")
+	}
+}
+
+func TestStreamEventsReconnectReauthenticates(t *testing.T) {
+	auths := make(chan map[string]string, 2)
+	upgrader := websocket.Upgrader{}
+	var connections int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var auth map[string]string
+		if err := conn.ReadJSON(&auth); err != nil {
+			return
+		}
+		auths <- auth
+		if atomic.AddInt32(&connections, 1) > 1 {
+			conn.ReadMessage()
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	client.StreamEventsWithOptions(ctx, "topic", "sub", StreamOptions{
+		Reconnect: true,
+		Backoff:   ConstantBackoff{Delay: time.Millisecond},
+	})
+
+	for i := 0; i < 2; i++ {
+		select {
+		case auth := <-auths:
+			if auth["token"] != "token" || auth["topic_slug"] != "topic" || auth["subscription_slug"] != "sub" {
+				t.Errorf("expected connection %d to authenticate, got %v", i+1, auth)
+			}
+		case <-ctx.Done():
+			t.Fatalf("expected connection %d to authenticate", i+1)
+		}
+	}
+}
+
+func TestStreamEventsMaxReconnects(t *testing.T) {
+	var upgrades int32
+	upgrader := websocket.Upgrader{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		// Only the first connection is accepted, and it's dropped straight away
+		if atomic.AddInt32(&upgrades, 1) > 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		conn.ReadMessage()
+		conn.Close()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	events, errs := client.StreamEventsWithOptions(ctx, "topic", "sub", StreamOptions{
+		Reconnect:     true,
+		MaxReconnects: 2,
+		Backoff:       ConstantBackoff{Delay: time.Millisecond},
+	})
+
+	err := <-errs
+	if err == nil || !strings.Contains(err.Error(), "failed to reconnect after 2 attempts") {
+		t.Fatalf("expected reconnecting to give up, got %v", err)
+	}
+	if _, ok := <-events; ok {
+		t.Error("expected the stream to end")
+	}
+	if n := atomic.LoadInt32(&upgrades); n != 3 {
+		t.Errorf("expected the first connection and two attempts, got %d", n)
+	}
+}
+
+func TestStreamEventsCancelDuringReconnect(t *testing.T) {
+	dropped := make(chan struct{}, 1)
+	client := newStreamClient(t, func(conn *websocket.Conn) {
+		dropped <- struct{}{}
+	})
+
+	// Cancelling races the error channel against the done channel, so try it a few times
+	for i := 0; i < 20; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		events, errs := client.StreamEventsWithOptions(ctx, "topic", "sub", StreamOptions{
+			Reconnect: true,
+			Backoff:   ConstantBackoff{Delay: time.Hour},
+		})
+
+		<-dropped
+		time.Sleep(time.Millisecond)
+		cancel()
+
+		if err, ok := <-errs; ok {
+			t.Fatalf("expected cancelling to end the stream quietly, got %v", err)
+		}
+		if _, ok := <-events; ok {
+			t.Fatal("expected the stream to end")
+		}
+	}
+}
+
+func TestStreamEventsOversizedMessageDoesNotReconnect(t *testing.T) {
+	var connections int32
+	client := newStreamClient(t, func(conn *websocket.Conn) {
+		atomic.AddInt32(&connections, 1)
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"id":"e1","data":{"a":"`+strings.Repeat("x", 2048)+`"}}`))
+		conn.ReadMessage()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, errs := client.StreamEventsWithOptions(ctx, "topic", "sub", StreamOptions{
+		MaxMessageBytes: 1024,
+		Reconnect:       true,
+		Backoff:         ConstantBackoff{Delay: time.Millisecond},
+	})
+
+	if err := <-errs; !errors.Is(err, ErrMessageTooLarge) {
+		t.Fatalf("expected ErrMessageTooLarge, got %v", err)
+	}
+	if n := atomic.LoadInt32(&connections); n != 1 {
+		t.Errorf("expected no reconnects, got %d connections", n)
+	}
+}
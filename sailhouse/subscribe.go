@@ -0,0 +1,441 @@
+package sailhouse
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type SubscriptionOptions struct {
+	OnError   func(error)
+	ExitOnErr bool
+	// ProcessedEvents receives every event the handler acknowledged. Sends never block;
+	// events are dropped if the channel is full, which is logged and counted by Metrics.
+	ProcessedEvents chan<- *Event
+	// OnErrorRateLimit reports repeated identical errors to OnError at most once per interval.
+	// Suppressed errors are counted and the count is included with the next one reported.
+	OnErrorRateLimit time.Duration
+	// LongPoll has the server hold each pull open until events are available, for up to
+	// LongPollTimeout. The next pull is sent straight away if events were returned or the server
+	// held the pull for close to LongPollTimeout, and after PollInterval otherwise, so a server
+	// that doesn't hold pulls isn't polled in a tight loop. Long-poll pulls aren't bound by the
+	// client's timeout, only by LongPollTimeout with a few seconds to spare.
+	LongPoll bool
+	// LongPollTimeout is how long the server may hold a pull open. Defaults to 20 seconds.
+	LongPollTimeout time.Duration
+	// Logger receives pull, ack and idempotency store failures with the topic, subscription and
+	// event ID. Defaults to the client's logger.
+	Logger *slog.Logger
+	// Metrics records processed events, handler durations, pull and ack errors, and retries.
+	Metrics MetricsRecorder
+	// AdaptivePolling halves the poll interval after a pull that returned events and doubles it
+	// after one that didn't, keeping it between MinPollInterval and MaxPollInterval.
+	AdaptivePolling bool
+	// MinPollInterval is the shortest interval used with AdaptivePolling. Defaults to 500ms.
+	MinPollInterval time.Duration
+	// MaxPollInterval is the longest interval used with AdaptivePolling. Defaults to 30 seconds.
+	MaxPollInterval time.Duration
+	// PollInterval is how long to wait between pulls. Defaults to 5 seconds.
+	PollInterval time.Duration
+	// Backoff lengthens the wait between pulls while no events are returned, going back to
+	// PollInterval as soon as events arrive. An ExponentialBackoff without a Base starts
+	// from PollInterval.
+	Backoff BackoffStrategy
+	// ErrorBackoff is the delay before pulling again after a failed pull. Defaults to
+	// exponential backoff from PollInterval up to a minute.
+	ErrorBackoff BackoffStrategy
+	// Idempotency skips events the store has already seen, acknowledging them without calling
+	// the handler. Events are marked in the store once the handler has acknowledged them.
+	Idempotency IdempotencyStore
+}
+
+const (
+	defaultLongPollTimeout = 20 * time.Second
+	// longPollGrace is how much longer than the server's hold time a long-poll pull may take
+	longPollGrace          = 5 * time.Second
+	defaultMinPollInterval = 500 * time.Millisecond
+	defaultMaxPollInterval = 30 * time.Second
+)
+
+// withBase starts an ExponentialBackoff without a Base from base.
+func withBase(b BackoffStrategy, base time.Duration) BackoffStrategy {
+	switch exp := b.(type) {
+	case ExponentialBackoff:
+		if exp.Base <= 0 {
+			exp.Base = base
+		}
+		return exp
+	case *ExponentialBackoff:
+		if exp.Base <= 0 {
+			copied := *exp
+			copied.Base = base
+			return copied
+		}
+	}
+
+	return b
+}
+
+// heldLongPoll reports whether the server held a long-poll pull for close to its timeout,
+// rather than answering straight away.
+func heldLongPoll(elapsed, timeout time.Duration) bool {
+	return elapsed >= timeout*9/10
+}
+
+// adaptInterval shortens the poll interval when events are flowing and lengthens it when idle.
+func adaptInterval(current, min, max time.Duration, gotEvents bool) time.Duration {
+	next := current * 2
+	if gotEvents {
+		next = current / 2
+	}
+
+	if next < min {
+		return min
+	}
+	if next > max {
+		return max
+	}
+
+	return next
+}
+
+type SubscriptionHandler func(context.Context, *Event)
+
+// Subscription is a running subscription started by Subscribe.
+type Subscription struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu       sync.Mutex
+	inFlight map[string]InFlightEvent
+}
+
+// InFlightEvent is an event being processed by a handler.
+type InFlightEvent struct {
+	ID           string
+	Topic        string
+	Subscription string
+	StartedAt    time.Time
+	// Attempt is the delivery attempt being handled. Handlers aren't retried by the client,
+	// so it's always 1.
+	Attempt int
+}
+
+func newSubscription(cancel context.CancelFunc) *Subscription {
+	return &Subscription{
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		inFlight: map[string]InFlightEvent{},
+	}
+}
+
+// InFlight returns a snapshot of the events the handler is processing, e.g. to debug a
+// subscription that seems stuck. Batches from SubscribeBatch are in flight while their
+// handler runs.
+func (s *Subscription) InFlight() []InFlightEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events := make([]InFlightEvent, 0, len(s.inFlight))
+	for _, e := range s.inFlight {
+		events = append(events, e)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].StartedAt.Before(events[j].StartedAt)
+	})
+
+	return events
+}
+
+func (s *Subscription) started(events ...*Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, e := range events {
+		s.inFlight[e.ID] = InFlightEvent{
+			ID:           e.ID,
+			Topic:        e.topic,
+			Subscription: e.subscription,
+			StartedAt:    now,
+			Attempt:      1,
+		}
+	}
+}
+
+func (s *Subscription) finished(events ...*Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range events {
+		delete(s.inFlight, e.ID)
+	}
+}
+
+// Done is closed once the subscription has stopped, whether through Stop, the context being
+// cancelled or ExitOnErr.
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Stop stops polling and blocks until the subscription has fully stopped, including any
+// handler that was running.
+func (s *Subscription) Stop() {
+	s.cancel()
+	<-s.done
+}
+
+// subscribeConfig is the SubscriptionOptions resolved against their defaults.
+type subscribeConfig struct {
+	pollInterval    time.Duration
+	errHandler      func(error)
+	exitOnErr       bool
+	processed       chan<- *Event
+	pullOpts        []getOption
+	longPollTimeout time.Duration
+	logger          *slog.Logger
+	metrics         MetricsRecorder
+	adaptive        bool
+	minInterval     time.Duration
+	maxInterval     time.Duration
+	idleBackoff     BackoffStrategy
+	errBackoff      BackoffStrategy
+	store           IdempotencyStore
+}
+
+func (c *SailhouseClient) subscribeConfig(opts *SubscriptionOptions) subscribeConfig {
+	cfg := subscribeConfig{
+		pollInterval: 5 * time.Second,
+		errHandler:   func(err error) {},
+		logger:       c.logger,
+		metrics:      noopMetrics{},
+		minInterval:  defaultMinPollInterval,
+		maxInterval:  defaultMaxPollInterval,
+	}
+
+	if opts != nil {
+		if opts.PollInterval > 0 {
+			cfg.pollInterval = opts.PollInterval
+		}
+
+		cfg.idleBackoff = withBase(opts.Backoff, cfg.pollInterval)
+
+		if opts.OnError != nil {
+			cfg.errHandler = opts.OnError
+		}
+
+		if opts.OnErrorRateLimit > 0 {
+			cfg.errHandler = rateLimitErrors(cfg.errHandler, opts.OnErrorRateLimit)
+		}
+
+		cfg.exitOnErr = opts.ExitOnErr
+		cfg.processed = opts.ProcessedEvents
+
+		if opts.Logger != nil {
+			cfg.logger = opts.Logger
+		}
+
+		if opts.Metrics != nil {
+			cfg.metrics = opts.Metrics
+		}
+
+		if opts.LongPoll {
+			cfg.longPollTimeout = opts.LongPollTimeout
+			if cfg.longPollTimeout <= 0 {
+				cfg.longPollTimeout = defaultLongPollTimeout
+			}
+			cfg.pullOpts = append(cfg.pullOpts, WithWait(cfg.longPollTimeout), WithResponseTimeout(cfg.longPollTimeout+longPollGrace))
+		}
+
+		cfg.adaptive = opts.AdaptivePolling
+		cfg.store = opts.Idempotency
+		if opts.MinPollInterval > 0 {
+			cfg.minInterval = opts.MinPollInterval
+		}
+		if opts.MaxPollInterval > 0 {
+			cfg.maxInterval = opts.MaxPollInterval
+		}
+	}
+
+	cfg.errBackoff = ExponentialBackoff{
+		Base:   cfg.pollInterval,
+		Max:    time.Minute,
+		Factor: 2,
+		Jitter: 0.2,
+	}
+	if opts != nil && opts.ErrorBackoff != nil {
+		cfg.errBackoff = opts.ErrorBackoff
+	}
+
+	return cfg
+}
+
+// skip reports whether an event should be skipped because the idempotency store has already
+// seen it, acknowledging it if so. Events the store can't be checked for are skipped without
+// being acknowledged, so they're redelivered once the store recovers.
+func (cfg subscribeConfig) skip(ctx context.Context, event *Event, topic, subscription string) bool {
+	if cfg.store == nil {
+		return false
+	}
+
+	seen, err := cfg.store.Seen(ctx, event.ID)
+	if err != nil {
+		cfg.logger.Error("failed to check event in idempotency store", "topic", topic, "subscription", subscription, "event_id", event.ID, "error", err)
+		cfg.errHandler(fmt.Errorf("failed to check event %s: %w", event.ID, err))
+		return true
+	}
+	if !seen {
+		return false
+	}
+
+	if err := event.Ack(ctx); err != nil {
+		cfg.metrics.IncAckError(topic, subscription)
+		cfg.logger.Error("failed to ack duplicate event", "topic", topic, "subscription", subscription, "event_id", event.ID, "error", err)
+		cfg.errHandler(err)
+	}
+
+	return true
+}
+
+// acked marks a processed event in the idempotency store and sends it on ProcessedEvents, if
+// it was acknowledged.
+func (cfg subscribeConfig) acked(ctx context.Context, event *Event, topic, subscription string) {
+	if !event.isAcked() {
+		return
+	}
+
+	if cfg.store != nil {
+		if err := cfg.store.Mark(ctx, event.ID); err != nil {
+			cfg.logger.Error("failed to mark event in idempotency store", "topic", topic, "subscription", subscription, "event_id", event.ID, "error", err)
+			cfg.errHandler(fmt.Errorf("failed to mark event %s: %w", event.ID, err))
+		}
+	}
+
+	if cfg.processed != nil {
+		select {
+		case cfg.processed <- event:
+		default:
+			cfg.metrics.IncProcessedDropped(topic, subscription)
+			cfg.logger.Warn("dropped processed event, channel full", "topic", topic, "subscription", subscription, "event_id", event.ID)
+		}
+	}
+}
+
+// Subscribe to a topic and subscription in the background, calling the handler function when new events are received.
+//
+// If an error is encountered, the `OnError` function within the SubscriptionOptions will be called.
+// The subscription runs until the context is cancelled or the returned Subscription is stopped.
+func (c *SailhouseClient) Subscribe(ctx context.Context, topic string, subscription string, handler SubscriptionHandler, opts *SubscriptionOptions) *Subscription {
+	ctx, cancel := context.WithCancel(orBackground(ctx))
+	sub := newSubscription(cancel)
+	cfg := c.subscribeConfig(opts)
+
+	go func() {
+		defer close(sub.done)
+		defer cancel()
+
+		failures := 0
+		empty := 0
+		interval := cfg.pollInterval
+		wait := interval
+		for {
+			if failures > 0 {
+				wait = cfg.errBackoff.NextDelay(failures - 1)
+			}
+
+			select {
+			case <-time.After(wait):
+				if failures > 0 {
+					cfg.metrics.IncRetry(topic, subscription)
+				}
+
+				pulledAt := time.Now()
+				events, err := c.GetEvents(ctx, topic, subscription, cfg.pullOpts...)
+				if err != nil && ctx.Err() != nil {
+					// Stopped while pulling
+					return
+				}
+				if err != nil {
+					failures++
+					cfg.metrics.IncError(topic, subscription)
+					cfg.logger.Error("failed to pull events", "topic", topic, "subscription", subscription, "error", err)
+					cfg.errHandler(err)
+					if cfg.exitOnErr {
+						return
+					}
+					continue
+				}
+				if failures > 0 {
+					cfg.errBackoff.Reset()
+				}
+				failures = 0
+
+				switch {
+				case cfg.adaptive:
+					interval = adaptInterval(interval, cfg.minInterval, cfg.maxInterval, len(events.Events) > 0)
+				case cfg.idleBackoff != nil && len(events.Events) == 0:
+					empty++
+					interval = cfg.idleBackoff.NextDelay(empty - 1)
+				case cfg.idleBackoff != nil:
+					if empty > 0 {
+						cfg.idleBackoff.Reset()
+					}
+					empty = 0
+					interval = cfg.pollInterval
+				}
+
+				// The server has already waited for events if it held the long poll
+				wait = interval
+				if cfg.longPollTimeout > 0 && (len(events.Events) > 0 || heldLongPoll(time.Since(pulledAt), cfg.longPollTimeout)) {
+					wait = 0
+				}
+
+				for _, event := range events.Events {
+					if cfg.skip(ctx, event, topic, subscription) {
+						continue
+					}
+
+					handlerCtx, span := c.startSpan(c.extractTraceContext(ctx, event), "sailhouse.process", "process", trace.SpanKindConsumer, topic, subscription)
+					span.SetAttributes(attribute.String("messaging.message.id", event.ID))
+					start := time.Now()
+					sub.started(event)
+					handler(handlerCtx, event)
+					sub.finished(event)
+					span.End()
+					cfg.metrics.ObserveHandlerDuration(topic, subscription, time.Since(start))
+					c.instruments.recordDuration(ctx, c.instruments.handlerDuration, time.Since(start), "process", topic, subscription)
+					cfg.metrics.IncProcessed(topic, subscription)
+
+					cfg.acked(ctx, event, topic, subscription)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return sub
+}
+
+// TopicSub identifies a subscription on a topic.
+type TopicSub struct {
+	Topic        string
+	Subscription string
+}
+
+// SubscribeAll subscribes the same handler to every topic and subscription pair, as if
+// Subscribe was called for each of them. The subscriptions are returned in the same order.
+func (c *SailhouseClient) SubscribeAll(ctx context.Context, pairs []TopicSub, handler SubscriptionHandler, opts *SubscriptionOptions) []*Subscription {
+	subs := make([]*Subscription, len(pairs))
+	for i, pair := range pairs {
+		subs[i] = c.Subscribe(ctx, pair.Topic, pair.Subscription, handler, opts)
+	}
+
+	return subs
+}
@@ -0,0 +1,145 @@
+package sailhouse
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// BatchHandler processes a batch of events in the order they arrived.
+type BatchHandler func(ctx context.Context, events []*Event) error
+
+// SubscribeBatch subscribes in the background like Subscribe, but collects events into batches
+// of up to size, handling a smaller batch once its first event has waited maxWait.
+//
+// Every event in the batch is acknowledged when the handler returns nil, and nacked so it's
+// redelivered when the handler fails. Options apply as they do to Subscribe, except LongPoll,
+// AdaptivePolling and Backoff, which are ignored as pulls are timed to fill the batch within
+// maxWait.
+func (c *SailhouseClient) SubscribeBatch(ctx context.Context, topic string, subscription string, size int, maxWait time.Duration, handler BatchHandler, opts *SubscriptionOptions) *Subscription {
+	ctx, cancel := context.WithCancel(orBackground(ctx))
+	sub := newSubscription(cancel)
+	cfg := c.subscribeConfig(opts)
+
+	if size <= 0 {
+		size = 1
+	}
+
+	flush := func(batch []*Event) {
+		start := time.Now()
+		sub.started(batch...)
+		err := handler(ctx, batch)
+		sub.finished(batch...)
+		cfg.metrics.ObserveHandlerDuration(topic, subscription, time.Since(start))
+		c.instruments.recordDuration(ctx, c.instruments.handlerDuration, time.Since(start), "process", topic, subscription)
+
+		if err != nil {
+			cfg.logger.Error("batch handler failed", "topic", topic, "subscription", subscription, "event_ids", eventIDs(batch), "error", err)
+			cfg.errHandler(fmt.Errorf("batch handler failed for %d events: %w", len(batch), err))
+
+			for _, event := range batch {
+				if err := event.Nack(ctx); err != nil {
+					cfg.metrics.IncAckError(topic, subscription)
+					cfg.logger.Error("failed to nack event", "topic", topic, "subscription", subscription, "event_id", event.ID, "error", err)
+					cfg.errHandler(fmt.Errorf("failed to nack event %s: %w", event.ID, err))
+				}
+			}
+			return
+		}
+
+		for _, event := range batch {
+			cfg.metrics.IncProcessed(topic, subscription)
+			if err := event.Ack(ctx); err != nil {
+				cfg.metrics.IncAckError(topic, subscription)
+				cfg.logger.Error("failed to ack event", "topic", topic, "subscription", subscription, "event_id", event.ID, "error", err)
+				cfg.errHandler(fmt.Errorf("failed to ack event %s: %w", event.ID, err))
+				continue
+			}
+			cfg.acked(ctx, event, topic, subscription)
+		}
+	}
+
+	go func() {
+		defer close(sub.done)
+		defer cancel()
+
+		var batch []*Event
+		var started time.Time
+		// Unacknowledged events are pulled again while the batch fills, so only add new ones
+		pending := map[string]bool{}
+		failures := 0
+		for {
+			wait := cfg.pollInterval
+			if failures > 0 {
+				wait = cfg.errBackoff.NextDelay(failures - 1)
+			}
+			if len(batch) > 0 {
+				if remaining := maxWait - time.Since(started); remaining < wait {
+					wait = remaining
+				}
+			}
+
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return
+			}
+
+			if failures > 0 {
+				cfg.metrics.IncRetry(topic, subscription)
+			}
+
+			// Pending events are redelivered first, so pull enough to fill the batch after them
+			events, err := c.GetEvents(ctx, topic, subscription, WithLimit(size))
+			if err != nil && ctx.Err() != nil {
+				// Stopped while pulling
+				return
+			}
+			if err != nil {
+				failures++
+				cfg.metrics.IncError(topic, subscription)
+				cfg.logger.Error("failed to pull events", "topic", topic, "subscription", subscription, "error", err)
+				cfg.errHandler(err)
+				if cfg.exitOnErr {
+					return
+				}
+			} else {
+				if failures > 0 {
+					cfg.errBackoff.Reset()
+				}
+				failures = 0
+
+				for _, event := range events.Events {
+					if len(batch) >= size {
+						break
+					}
+					if pending[event.ID] || cfg.skip(ctx, event, topic, subscription) {
+						continue
+					}
+					if len(batch) == 0 {
+						started = time.Now()
+					}
+					pending[event.ID] = true
+					batch = append(batch, event)
+				}
+			}
+
+			if len(batch) > 0 && (len(batch) >= size || time.Since(started) >= maxWait) {
+				flush(batch)
+				batch = nil
+				pending = map[string]bool{}
+			}
+		}
+	}()
+
+	return sub
+}
+
+func eventIDs(events []*Event) []string {
+	ids := make([]string, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+
+	return ids
+}
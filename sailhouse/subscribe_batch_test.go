@@ -0,0 +1,261 @@
+package sailhouse
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// batchServer serves one new event per pull, or the same event until it's acked when repeat
+// is set, and records the acks it receives.
+type batchServer struct {
+	repeat bool
+	pulls  int32
+	mu     sync.Mutex
+	acks   []string
+}
+
+func (s *batchServer) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		n := atomic.AddInt32(&s.pulls, 1)
+		if s.repeat {
+			if len(s.acked()) > 0 {
+				w.Write([]byte(`{"events":[]}`))
+				return
+			}
+			n = 1
+		}
+		fmt.Fprintf(w, `{"events":[{"id":"e%d","data":{}}]}`, n)
+		return
+	}
+
+	parts := strings.Split(r.URL.Path, "/")
+	s.mu.Lock()
+	s.acks = append(s.acks, parts[len(parts)-1])
+	s.mu.Unlock()
+}
+
+func (s *batchServer) acked() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.acks...)
+}
+
+func TestSubscribeBatchSizeTriggered(t *testing.T) {
+	srv := &batchServer{}
+	client := newTestClient(t, srv.handle)
+
+	batches := make(chan []string, 1)
+	sub := client.SubscribeBatch(context.Background(), "topic", "sub", 3, time.Hour, func(ctx context.Context, events []*Event) error {
+		batches <- eventIDs(events)
+		return nil
+	}, &SubscriptionOptions{PollInterval: time.Millisecond})
+	defer sub.Stop()
+
+	select {
+	case got := <-batches:
+		if strings.Join(got, ",") != "e1,e2,e3" {
+			t.Errorf("expected e1,e2,e3 in arrival order, got %v", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a full batch")
+	}
+}
+
+func TestSubscribeBatchTimeTriggered(t *testing.T) {
+	srv := &batchServer{}
+	client := newTestClient(t, srv.handle)
+
+	batches := make(chan []string, 1)
+	start := time.Now()
+	sub := client.SubscribeBatch(context.Background(), "topic", "sub", 1000, 50*time.Millisecond, func(ctx context.Context, events []*Event) error {
+		batches <- eventIDs(events)
+		return nil
+	}, &SubscriptionOptions{PollInterval: 10 * time.Millisecond})
+	defer sub.Stop()
+
+	select {
+	case got := <-batches:
+		if len(got) == 0 || len(got) >= 1000 {
+			t.Errorf("expected a partial batch, got %d events", len(got))
+		}
+		if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+			t.Errorf("expected the batch to wait for maxWait, flushed after %s", elapsed)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a batch once maxWait passed")
+	}
+}
+
+func TestSubscribeBatchSkipsRepeatedEvents(t *testing.T) {
+	srv := &batchServer{repeat: true}
+	client := newTestClient(t, srv.handle)
+
+	batches := make(chan []string, 1)
+	sub := client.SubscribeBatch(context.Background(), "topic", "sub", 5, 30*time.Millisecond, func(ctx context.Context, events []*Event) error {
+		batches <- eventIDs(events)
+		return nil
+	}, &SubscriptionOptions{PollInterval: time.Millisecond})
+
+	var got []string
+	select {
+	case got = <-batches:
+	case <-time.After(time.Second):
+		t.Fatal("expected a batch")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(srv.acked()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	sub.Stop()
+
+	if strings.Join(got, ",") != "e1" {
+		t.Errorf("expected the repeated event once, got %v", got)
+	}
+	if acks := srv.acked(); len(acks) != 1 {
+		t.Errorf("expected a single ack, got %v", acks)
+	}
+}
+
+func TestSubscribeBatchLogsHandlerAndNackFailures(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"events":[{"id":"e1","data":{}}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	var logs bytes.Buffer
+	nacked := make(chan struct{}, 1)
+	sub := client.SubscribeBatch(context.Background(), "topic", "sub", 1, time.Hour, func(ctx context.Context, events []*Event) error {
+		return errors.New("insert failed")
+	}, &SubscriptionOptions{
+		PollInterval: time.Millisecond,
+		Logger:       slog.New(slog.NewTextHandler(&logs, nil)),
+		OnError: func(err error) {
+			if strings.Contains(err.Error(), "nack") {
+				select {
+				case nacked <- struct{}{}:
+				default:
+				}
+			}
+		},
+	})
+
+	select {
+	case <-nacked:
+	case <-time.After(time.Second):
+		t.Fatal("expected the nack to fail")
+	}
+	sub.Stop()
+
+	for _, want := range []string{"batch handler failed", "failed to nack event", "event_id=e1"} {
+		if !strings.Contains(logs.String(), want) {
+			t.Errorf("expected %q in logs, got %q", want, logs.String())
+		}
+	}
+}
+
+func TestSubscribeBatchFillsAlongsideRedeliveredEvents(t *testing.T) {
+	var mu sync.Mutex
+	var unacked []string
+	arrived := 0
+
+	// Each pull sees one new event arrive, and redelivers unacked events before new ones
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method != http.MethodGet {
+			id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+			for i, unackedID := range unacked {
+				if unackedID == id {
+					unacked = append(unacked[:i], unacked[i+1:]...)
+					break
+				}
+			}
+			return
+		}
+
+		arrived++
+		unacked = append(unacked, fmt.Sprintf("e%d", arrived))
+
+		var limit int
+		fmt.Sscan(r.URL.Query().Get("limit"), &limit)
+		events := unacked
+		if limit > 0 && len(events) > limit {
+			events = events[:limit]
+		}
+
+		var body []string
+		for _, id := range events {
+			body = append(body, `{"id":"`+id+`","data":{}}`)
+		}
+		fmt.Fprintf(w, `{"events":[%s]}`, strings.Join(body, ","))
+	})
+
+	batches := make(chan []string, 1)
+	sub := client.SubscribeBatch(context.Background(), "topic", "sub", 3, time.Hour, func(ctx context.Context, events []*Event) error {
+		batches <- eventIDs(events)
+		return nil
+	}, &SubscriptionOptions{PollInterval: time.Millisecond})
+	defer sub.Stop()
+
+	select {
+	case got := <-batches:
+		if strings.Join(got, ",") != "e1,e2,e3" {
+			t.Errorf("expected e1,e2,e3, got %v", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the batch to fill despite redelivered events")
+	}
+}
+
+func TestSubscribeBatchSharesSubscribeOptions(t *testing.T) {
+	srv := &batchServer{}
+	client := newTestClient(t, srv.handle)
+
+	store := NewMemoryIdempotencyStore(0)
+	store.Mark(context.Background(), "e1")
+	processed := make(chan *Event, 10)
+
+	batches := make(chan []string, 1)
+	sub := client.SubscribeBatch(context.Background(), "topic", "sub", 2, time.Hour, func(ctx context.Context, events []*Event) error {
+		batches <- eventIDs(events)
+		return nil
+	}, &SubscriptionOptions{PollInterval: time.Millisecond, Idempotency: store, ProcessedEvents: processed})
+
+	var got []string
+	select {
+	case got = <-batches:
+	case <-time.After(time.Second):
+		t.Fatal("expected a batch")
+	}
+
+	for _, want := range []string{"e2", "e3"} {
+		select {
+		case event := <-processed:
+			if event.ID != want {
+				t.Errorf("expected %s to be processed, got %s", want, event.ID)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected %s on ProcessedEvents", want)
+		}
+	}
+	sub.Stop()
+
+	if strings.Join(got, ",") != "e2,e3" {
+		t.Errorf("expected the seen e1 to be skipped, got %v", got)
+	}
+}
@@ -0,0 +1,575 @@
+package sailhouse
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// failingStore reports every event as seen, or fails when err is set.
+type failingStore struct {
+	err error
+}
+
+func (s failingStore) Seen(context.Context, string) (bool, error) { return s.err == nil, s.err }
+func (s failingStore) Mark(context.Context, string) error         { return s.err }
+
+func TestSubscribeLogsFailuresWithEventID(t *testing.T) {
+	tests := []struct {
+		name  string
+		store IdempotencyStore
+		want  string
+	}{
+		{name: "ack of duplicate", store: failingStore{}, want: "failed to ack duplicate event"},
+		{name: "store check", store: failingStore{err: errors.New("store down")}, want: "failed to check event in idempotency store"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet {
+					w.Write([]byte(`{"events":[{"id":"e1","data":{}}]}`))
+					return
+				}
+				w.WriteHeader(http.StatusInternalServerError)
+			})
+
+			var logs bytes.Buffer
+			errs := make(chan error, 10)
+			sub := client.Subscribe(context.Background(), "topic", "sub", func(context.Context, *Event) {}, &SubscriptionOptions{
+				PollInterval: time.Millisecond,
+				Idempotency:  tt.store,
+				Logger:       slog.New(slog.NewTextHandler(&logs, nil)),
+				OnError: func(err error) {
+					select {
+					case errs <- err:
+					default:
+					}
+				},
+			})
+
+			select {
+			case <-errs:
+			case <-time.After(time.Second):
+				t.Fatal("expected an error")
+			}
+			sub.Stop()
+
+			if !strings.Contains(logs.String(), tt.want) || !strings.Contains(logs.String(), "event_id=e1") {
+				t.Errorf("expected %q logged with the event ID, got %q", tt.want, logs.String())
+			}
+		})
+	}
+}
+
+func TestSubscriptionStopDuringPullReportsNoError(t *testing.T) {
+	pulling := make(chan struct{}, 1)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case pulling <- struct{}{}:
+		default:
+		}
+		<-r.Context().Done()
+	})
+
+	errs := make(chan error, 1)
+	sub := client.Subscribe(context.Background(), "topic", "sub", func(context.Context, *Event) {}, &SubscriptionOptions{
+		PollInterval: time.Millisecond,
+		OnError:      func(err error) { errs <- err },
+	})
+
+	<-pulling
+	sub.Stop()
+
+	select {
+	case <-sub.Done():
+	default:
+		t.Fatal("expected Done to be closed once Stop returns")
+	}
+
+	select {
+	case err := <-errs:
+		t.Errorf("expected no error from stopping, got %v", err)
+	default:
+	}
+}
+
+func TestSubscriptionInFlightDuringSlowHandler(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"events":[{"id":"e1","data":{}}]}`))
+	})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	sub := client.Subscribe(context.Background(), "topic", "sub", func(ctx context.Context, e *Event) {
+		once.Do(func() {
+			close(started)
+			<-release
+		})
+	}, &SubscriptionOptions{PollInterval: time.Millisecond})
+	defer sub.Stop()
+
+	<-started
+	inFlight := sub.InFlight()
+	close(release)
+
+	if len(inFlight) != 1 {
+		t.Fatalf("expected one in-flight event, got %v", inFlight)
+	}
+	got := inFlight[0]
+	if got.ID != "e1" || got.Topic != "topic" || got.Subscription != "sub" || got.Attempt != 1 || got.StartedAt.IsZero() {
+		t.Errorf("unexpected in-flight event %+v", got)
+	}
+}
+
+func TestSubscribeBatchInFlight(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"events":[{"id":"e1","data":{}}]}`))
+	})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	sub := client.SubscribeBatch(context.Background(), "topic", "sub", 1, time.Hour, func(ctx context.Context, events []*Event) error {
+		once.Do(func() {
+			close(started)
+			<-release
+		})
+		return nil
+	}, &SubscriptionOptions{PollInterval: time.Millisecond})
+	defer sub.Stop()
+
+	<-started
+	inFlight := sub.InFlight()
+	close(release)
+
+	if len(inFlight) != 1 || inFlight[0].ID != "e1" {
+		t.Fatalf("expected e1 in flight, got %v", inFlight)
+	}
+}
+
+func TestAdaptInterval(t *testing.T) {
+	min, max := 10*time.Millisecond, 80*time.Millisecond
+
+	tests := []struct {
+		name      string
+		current   time.Duration
+		gotEvents bool
+		want      time.Duration
+	}{
+		{"shrinks under load", 40 * time.Millisecond, true, 20 * time.Millisecond},
+		{"grows when idle", 40 * time.Millisecond, false, 80 * time.Millisecond},
+		{"bounded by min", 15 * time.Millisecond, true, min},
+		{"bounded by max", 60 * time.Millisecond, false, max},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := adaptInterval(tt.current, min, max, tt.gotEvents); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSubscribeAdaptivePolling(t *testing.T) {
+	var mu sync.Mutex
+	var pulls []time.Time
+	done := make(chan struct{})
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		pulls = append(pulls, time.Now())
+		switch {
+		case len(pulls) <= 3:
+			w.Write([]byte(`{"events":[{"id":"e1","data":{}}]}`))
+			return
+		case len(pulls) == 9:
+			close(done)
+		}
+		w.Write([]byte(`{"events":[]}`))
+	})
+
+	sub := client.Subscribe(context.Background(), "topic", "sub", func(ctx context.Context, e *Event) {}, &SubscriptionOptions{
+		PollInterval:    40 * time.Millisecond,
+		AdaptivePolling: true,
+		MinPollInterval: 5 * time.Millisecond,
+		MaxPollInterval: 80 * time.Millisecond,
+	})
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected nine pulls")
+	}
+	sub.Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// Three busy pulls take the interval down to the minimum, five idle ones back up to the maximum
+	busy := pulls[3].Sub(pulls[2])
+	idle := pulls[8].Sub(pulls[7])
+	if busy >= idle/2 {
+		t.Errorf("expected the interval to shrink under load and grow when idle, got %v busy and %v idle", busy, idle)
+	}
+}
+
+func TestSubscribeSkipsDuplicateEvents(t *testing.T) {
+	var mu sync.Mutex
+	acked := map[string]bool{}
+	bothAcked := make(chan struct{})
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method == http.MethodPost {
+			acked[r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]] = true
+			if len(acked) == 2 {
+				close(bothAcked)
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		var events []string
+		for _, id := range []string{"e1", "e2"} {
+			if !acked[id] {
+				events = append(events, `{"id":"`+id+`","data":{}}`)
+			}
+		}
+		w.Write([]byte(`{"events":[` + strings.Join(events, ",") + `]}`))
+	})
+
+	store := NewMemoryIdempotencyStore(0)
+	store.Mark(context.Background(), "e1")
+
+	handled := make(chan string, 10)
+	sub := client.Subscribe(context.Background(), "topic", "sub", func(ctx context.Context, e *Event) {
+		handled <- e.ID
+		e.Ack(ctx)
+	}, &SubscriptionOptions{PollInterval: time.Millisecond, Idempotency: store})
+
+	select {
+	case <-bothAcked:
+	case <-time.After(time.Second):
+		t.Fatal("expected the duplicate and the new event to be acked")
+	}
+
+	// The handled event is marked once its ack has returned
+	deadline := time.Now().Add(time.Second)
+	for seen, _ := store.Seen(context.Background(), "e2"); !seen; seen, _ = store.Seen(context.Background(), "e2") {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the handled event to be marked in the store")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	sub.Stop()
+	close(handled)
+
+	for id := range handled {
+		if id != "e2" {
+			t.Errorf("expected the handler to skip the duplicate, got %s", id)
+		}
+	}
+}
+
+func TestSubscribeLongPoll(t *testing.T) {
+	// countEmptyPolls runs a subscription for a while against a server that holds requests
+	// asking to wait, returning how many empty polls it answered.
+	countEmptyPolls := func(t *testing.T, opts *SubscriptionOptions) (int32, []string) {
+		var mu sync.Mutex
+		var polls int32
+		var waits []string
+
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			wait := r.URL.Query().Get("wait")
+			mu.Lock()
+			polls++
+			waits = append(waits, wait)
+			mu.Unlock()
+
+			if d, err := time.ParseDuration(wait); err == nil {
+				select {
+				case <-time.After(d):
+				case <-r.Context().Done():
+				}
+			}
+			w.Write([]byte(`{"events":[]}`))
+		})
+
+		sub := client.Subscribe(context.Background(), "topic", "sub", func(ctx context.Context, e *Event) {}, opts)
+		time.Sleep(200 * time.Millisecond)
+		sub.Stop()
+
+		mu.Lock()
+		defer mu.Unlock()
+		return polls, waits
+	}
+
+	short, _ := countEmptyPolls(t, &SubscriptionOptions{PollInterval: 5 * time.Millisecond})
+	long, waits := countEmptyPolls(t, &SubscriptionOptions{
+		PollInterval:    5 * time.Millisecond,
+		LongPoll:        true,
+		LongPollTimeout: 50 * time.Millisecond,
+	})
+
+	for _, wait := range waits {
+		if wait != "50ms" {
+			t.Fatalf("expected every pull to ask the server to wait 50ms, got %q", wait)
+		}
+	}
+	if long == 0 || long >= short/2 {
+		t.Errorf("expected long polling to cut empty polls, got %d against %d", long, short)
+	}
+}
+
+func TestSubscribeLongPollServerIgnoringWait(t *testing.T) {
+	var polls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&polls, 1)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	sub := client.Subscribe(context.Background(), "topic", "sub", func(ctx context.Context, e *Event) {}, &SubscriptionOptions{
+		PollInterval:    20 * time.Millisecond,
+		LongPoll:        true,
+		LongPollTimeout: time.Second,
+	})
+	time.Sleep(200 * time.Millisecond)
+	sub.Stop()
+
+	// Empty polls the server answered straight away fall back to PollInterval
+	if n := atomic.LoadInt32(&polls); n > 15 {
+		t.Errorf("expected roughly one pull per PollInterval, got %d in 200ms", n)
+	}
+}
+
+func TestSubscribeFirstErrorDelayIsBase(t *testing.T) {
+	var mu sync.Mutex
+	var pulls []time.Time
+	retried := make(chan struct{})
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		pulls = append(pulls, time.Now())
+		switch len(pulls) {
+		case 1:
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		case 2:
+			close(retried)
+		}
+		w.Write([]byte(`{"events":[]}`))
+	})
+
+	sub := client.Subscribe(context.Background(), "topic", "sub", func(ctx context.Context, e *Event) {}, &SubscriptionOptions{
+		PollInterval: 50 * time.Millisecond,
+	})
+
+	select {
+	case <-retried:
+	case <-time.After(time.Second):
+		t.Fatal("expected the failed pull to be retried")
+	}
+	sub.Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// The default error backoff starts at PollInterval with 20% jitter
+	if delay := pulls[1].Sub(pulls[0]); delay < 35*time.Millisecond || delay > 75*time.Millisecond {
+		t.Errorf("expected the first retry after about 50ms, got %v", delay)
+	}
+}
+
+func TestSubscribeIdleBackoff(t *testing.T) {
+	var pulls int32
+	done := make(chan struct{})
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		// Three empty polls, one with an event, then idle again
+		switch atomic.AddInt32(&pulls, 1) {
+		case 4:
+			w.Write([]byte(`{"events":[{"id":"e1","data":{}}]}`))
+			return
+		case 6:
+			close(done)
+		}
+		w.Write([]byte(`{"events":[]}`))
+	})
+
+	backoff := &recordingBackoff{}
+	sub := client.Subscribe(context.Background(), "topic", "sub", func(ctx context.Context, e *Event) {}, &SubscriptionOptions{
+		PollInterval: time.Millisecond,
+		Backoff:      backoff,
+	})
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected six pulls")
+	}
+	sub.Stop()
+
+	backoff.mu.Lock()
+	defer backoff.mu.Unlock()
+
+	if len(backoff.attempts) < 4 || !reflect.DeepEqual(backoff.attempts[:4], []int{0, 1, 2, 0}) {
+		t.Errorf("expected idle attempts from 0, starting again after events, got %v", backoff.attempts)
+	}
+	if backoff.resets != 1 {
+		t.Errorf("expected one reset once events arrived, got %d", backoff.resets)
+	}
+}
+
+// ackingServer serves the given events until each is acked.
+func ackingServer(t *testing.T, ids ...string) *SailhouseClient {
+	t.Helper()
+
+	var mu sync.Mutex
+	acked := map[string]bool{}
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method == http.MethodPost {
+			acked[r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]] = true
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		var events []string
+		for _, id := range ids {
+			if !acked[id] {
+				events = append(events, `{"id":"`+id+`","data":{}}`)
+			}
+		}
+		w.Write([]byte(`{"events":[` + strings.Join(events, ",") + `]}`))
+	})
+}
+
+func TestSubscribeProcessedEventsOnlyAcked(t *testing.T) {
+	client := ackingServer(t, "e1", "e2")
+
+	var e2Handled int32
+	processed := make(chan *Event, 10)
+	sub := client.Subscribe(context.Background(), "topic", "sub", func(ctx context.Context, e *Event) {
+		if e.ID == "e2" {
+			atomic.AddInt32(&e2Handled, 1)
+			return
+		}
+		e.Ack(ctx)
+	}, &SubscriptionOptions{PollInterval: time.Millisecond, ProcessedEvents: processed})
+
+	select {
+	case event := <-processed:
+		if event.ID != "e1" {
+			t.Errorf("expected the acked e1, got %s", event.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the acked event on ProcessedEvents")
+	}
+
+	waitFor(t, func() bool { return atomic.LoadInt32(&e2Handled) >= 3 })
+	sub.Stop()
+	close(processed)
+
+	for event := range processed {
+		t.Errorf("expected the unacked event to be left out, got %s", event.ID)
+	}
+}
+
+func TestSubscribeProcessedEventsDropsWhenFull(t *testing.T) {
+	client := ackingServer(t, "e1", "e2", "e3")
+
+	metrics := &countingMetrics{}
+	processed := make(chan *Event, 1)
+	sub := client.Subscribe(context.Background(), "topic", "sub", func(ctx context.Context, e *Event) {
+		e.Ack(ctx)
+	}, &SubscriptionOptions{PollInterval: time.Millisecond, ProcessedEvents: processed, Metrics: metrics})
+
+	waitFor(t, func() bool { return atomic.LoadInt32(&metrics.processed) == 3 })
+	sub.Stop()
+
+	if event := <-processed; event.ID != "e1" {
+		t.Errorf("expected the first event to fit, got %s", event.ID)
+	}
+	if n := atomic.LoadInt32(&metrics.dropped); n != 2 {
+		t.Errorf("expected two dropped events, got %d", n)
+	}
+}
+
+func TestSubscribeAll(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		// Each pair gets an event named after its subscription
+		parts := strings.Split(r.URL.Path, "/")
+		w.Write([]byte(`{"events":[{"id":"` + parts[4] + `","data":{}}]}`))
+	})
+
+	pairs := []TopicSub{
+		{Topic: "orders", Subscription: "billing"},
+		{Topic: "users", Subscription: "audit"},
+	}
+
+	release := make(chan struct{})
+	handled := make(chan TopicSub, 2)
+	subs := client.SubscribeAll(context.Background(), pairs, func(ctx context.Context, e *Event) {
+		if e.ID != e.Subscription() {
+			t.Errorf("expected %s's event, got %s", e.Subscription(), e.ID)
+		}
+		select {
+		case handled <- TopicSub{Topic: e.Topic(), Subscription: e.Subscription()}:
+		default:
+		}
+		<-release
+	}, &SubscriptionOptions{PollInterval: time.Millisecond})
+
+	seen := map[TopicSub]bool{}
+	for len(seen) < len(pairs) {
+		select {
+		case pair := <-handled:
+			seen[pair] = true
+		case <-time.After(time.Second):
+			t.Fatalf("expected every pair to be pulled, got %v", seen)
+		}
+	}
+
+	if len(subs) != len(pairs) {
+		t.Fatalf("expected a subscription per pair, got %d", len(subs))
+	}
+	// Each handler is blocked, so each subscription's in-flight event shows which pair it's for
+	for i, sub := range subs {
+		inFlight := sub.InFlight()
+		if len(inFlight) != 1 || inFlight[0].Topic != pairs[i].Topic || inFlight[0].Subscription != pairs[i].Subscription {
+			t.Errorf("expected subscription %d to be for %v, got %v", i, pairs[i], inFlight)
+		}
+	}
+
+	close(release)
+	for _, sub := range subs {
+		sub.Stop()
+	}
+}
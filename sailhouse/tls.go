@@ -0,0 +1,42 @@
+package sailhouse
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+)
+
+// skipTLSDialer returns a TLS dial function that only skips certificate
+// verification for connections to the given hosts. Every other host is
+// verified as usual.
+//
+// Connections use a clone of base, when given, offering the protocols in protos.
+func skipTLSDialer(hosts []string, base *tls.Config, protos []string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	skip := make(map[string]bool, len(hosts))
+	for _, host := range hosts {
+		skip[host] = true
+	}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			host = addr
+		}
+
+		config := &tls.Config{}
+		if base != nil {
+			config = base.Clone()
+		}
+		if config.ServerName == "" {
+			config.ServerName = host
+		}
+		config.NextProtos = protos
+		if skip[host] {
+			config.InsecureSkipVerify = true
+		}
+
+		dialer := &tls.Dialer{Config: config}
+
+		return dialer.DialContext(ctx, network, addr)
+	}
+}
@@ -0,0 +1,72 @@
+package sailhouse
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTLSTestServer(t *testing.T, protos chan<- string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case protos <- r.Proto:
+		default:
+		}
+		w.Write([]byte(`{"events":[]}`))
+	}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestSkipTLSForHostsOnlySkipsListedHosts(t *testing.T) {
+	srv := newTLSTestServer(t, nil)
+
+	tests := []struct {
+		name    string
+		hosts   []string
+		wantErr bool
+	}{
+		{name: "listed host", hosts: []string{"127.0.0.1"}},
+		{name: "other host", hosts: []string{"localhost"}, wantErr: true},
+		{name: "host with port", hosts: []string{srv.Listener.Addr().String()}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewSailhouseClientWithOptions(SailhouseClientOptions{
+				Token:           "token",
+				BaseURL:         srv.URL,
+				SkipTLSForHosts: tt.hosts,
+			})
+
+			_, err := client.GetEvents(context.Background(), "topic", "sub")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSkipTLSForHostsKeepsHTTP2(t *testing.T) {
+	protos := make(chan string, 1)
+	srv := newTLSTestServer(t, protos)
+
+	client := NewSailhouseClientWithOptions(SailhouseClientOptions{
+		Token:           "token",
+		BaseURL:         srv.URL,
+		SkipTLSForHosts: []string{"127.0.0.1"},
+	})
+
+	if _, err := client.GetEvents(context.Background(), "topic", "sub"); err != nil {
+		t.Fatal(err)
+	}
+	if proto := <-protos; proto != "HTTP/2.0" {
+		t.Errorf("expected HTTP/2 to be negotiated, got %s", proto)
+	}
+}
@@ -0,0 +1,89 @@
+package sailhouse
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const instrumentationName = "github.com/sailhouse/sdk-go/sailhouse"
+
+// traceContext carries trace context between publishers and consumers in event metadata.
+var traceContext = propagation.TraceContext{}
+
+func newTracer(tp trace.TracerProvider) trace.Tracer {
+	if tp == nil {
+		return noop.NewTracerProvider().Tracer(instrumentationName)
+	}
+
+	return tp.Tracer(instrumentationName)
+}
+
+// startSpan starts a span following the OpenTelemetry messaging conventions.
+func (c *SailhouseClient) startSpan(ctx context.Context, name, operation string, kind trace.SpanKind, topic, subscription string) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		attribute.String("messaging.system", "sailhouse"),
+		attribute.String("messaging.operation", operation),
+		attribute.String("messaging.destination.name", topic),
+	}
+	if subscription != "" {
+		attrs = append(attrs, attribute.String("messaging.sailhouse.subscription", subscription))
+	}
+
+	return c.tracer.Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+}
+
+// endSpan records the outcome of an operation on its span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// injectTraceContext adds the trace context of ctx to an event's metadata, copying rather than
+// modifying the caller's metadata map.
+func (c *SailhouseClient) injectTraceContext(ctx context.Context, body map[string]any) {
+	if !c.tracing {
+		return
+	}
+
+	carrier := propagation.MapCarrier{}
+	traceContext.Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return
+	}
+
+	metadata := map[string]any{}
+	if existing, ok := body[FieldMetadata].(map[string]any); ok {
+		for k, v := range existing {
+			metadata[k] = v
+		}
+	}
+	for k, v := range carrier {
+		metadata[k] = v
+	}
+
+	body[FieldMetadata] = metadata
+}
+
+// extractTraceContext returns ctx with the trace context carried in an event's metadata.
+func (c *SailhouseClient) extractTraceContext(ctx context.Context, e *Event) context.Context {
+	if !c.tracing || len(e.Metadata) == 0 {
+		return ctx
+	}
+
+	carrier := propagation.MapCarrier{}
+	for _, key := range traceContext.Fields() {
+		if v, ok := e.Metadata[key].(string); ok {
+			carrier[key] = v
+		}
+	}
+
+	return traceContext.Extract(ctx, carrier)
+}